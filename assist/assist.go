@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/jonseymour/12coins/lib"
+	"io"
+	"os"
+	"strings"
+)
+
+// Load and reverse the solution in the specified file.
+func load(file string) (*lib.Solution, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s := &lib.Solution{}
+	if err := json.NewDecoder(f).Decode(s); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	s.DecodeJSON()
+	return s.Reset().Reverse()
+}
+
+// Print the weighings to perform, then read the observed results, one outcome such as
+// "LEH" or "L E H" per line, and print the counterfeit coin each outcome implies.
+// Each letter describes the left pan: L if it is lighter, E if the pans balance and
+// H if it is heavier. Answers the exit code.
+func assist(s *lib.Solution, in io.Reader, out io.Writer) int {
+	z := s.GetZeroCoin()
+	fmt.Fprintf(out, "perform these weighings:\n")
+	for i, w := range s.Weighings {
+		fmt.Fprintf(out, "%d: %v v %v\n", i+1, w.Left().AsCoins(z), w.Right().AsCoins(z))
+	}
+	fmt.Fprintf(out, "then enter the %d results as L, E or H for the left pan, for example: %s\n",
+		len(s.Weighings), strings.Repeat("E", len(s.Weighings)-1)+"H")
+
+	code := 0
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "results> ")
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.Join(strings.Fields(scanner.Text()), "")
+		if line == "" {
+			continue
+		}
+		o, err := lib.ParseOutcome(line)
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+			code = 1
+			continue
+		}
+		c, w, err := s.DecideOutcome(o)
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+			code = 1
+			continue
+		}
+		if w == lib.Equal {
+			fmt.Fprintf(out, "the counterfeit coin is %d, but its weight can't be determined\n", c)
+		} else {
+			fmt.Fprintf(out, "the counterfeit coin is %d, which is %v\n", c, w)
+		}
+	}
+	fmt.Fprintf(out, "\n")
+	return code
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: assist solution.json\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	s, err := load(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(assist(s, os.Stdin, os.Stdout))
+}
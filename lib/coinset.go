@@ -10,7 +10,8 @@ const (
 	ONE_BASED  = 1
 )
 
-type CoinMask uint16
+// A mask of at most 64 coins.
+type CoinMask uint64
 
 type CoinSet interface {
 	AsCoins(zeroCoin int) []int
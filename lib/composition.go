@@ -0,0 +1,60 @@
+package lib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Answer the composition of each pan of each weighing of a grouped solution, in the
+// notation of structure.go: T for a triple, J for a member of a pair that is joined by
+// the weighing, L and R for the left and right halves of a pair that is split by the
+// weighing, U for a singleton and G for the reference coin. For example, the pans of a
+// P weighing of the 12 coins problem are (2T, 1L, 1U) and (1T, 2J, 1R).
+func (s *Solution) composition() [][2]string {
+	z := s.GetZeroCoin()
+	g, reference := s.GenuineCoin()
+	result := make([][2]string, len(s.Weighings))
+	for j, w := range s.Weighings {
+		for p, pan := range w.Pans() {
+			counts := map[string]int{}
+			for _, c := range pan.AsCoins(z) {
+				counts[s.role(w, c, reference && c == g)]++
+			}
+			parts := []string{}
+			for _, r := range []string{"T", "J", "L", "R", "U", "G"} {
+				if counts[r] > 0 {
+					parts = append(parts, fmt.Sprintf("%d%s", counts[r], r))
+				}
+			}
+			result[j][p] = "(" + strings.Join(parts, ", ") + ")"
+		}
+	}
+	return result
+}
+
+// Answer the role of coin c in weighing w.
+func (s *Solution) role(w Weighing, c int, genuine bool) string {
+	z := s.GetZeroCoin()
+	coin := NewCoinSet([]int{c}, z)
+	switch {
+	case genuine:
+		return "G"
+	case s.Triples.Intersection(coin).Size() == 1:
+		return "T"
+	case s.Unique.Intersection(coin).Size() == 1:
+		return "U"
+	}
+	for _, pair := range s.Pairs {
+		if pair.Intersection(coin).Size() == 0 {
+			continue
+		}
+		if pair.Intersection(w.Left()).Size() == 1 && pair.Intersection(w.Right()).Size() == 1 {
+			if w.Left().Intersection(coin).Size() == 1 {
+				return "L"
+			}
+			return "R"
+		}
+		return "J"
+	}
+	panic(fmt.Errorf("illegal state: coin %d is not grouped", c))
+}
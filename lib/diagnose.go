@@ -0,0 +1,162 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// An Outcome is the sequence of results of the weighings of a solution, written
+// as a string of L, E and H, for example "LEH".
+type Outcome []Weight
+
+// An Ambiguity is an outcome that is produced by more than one hypothesis about
+// the counterfeit coin and its relative weight.
+type Ambiguity struct {
+	Outcome    Outcome       `json:"outcome"`
+	Hypotheses []Counterfeit `json:"hypotheses"`
+}
+
+// A Diagnosis describes why the weighings of a solution do or do not identify the
+// counterfeit coin.
+type Diagnosis struct {
+	Valid     bool        `json:"valid"`
+	Ambiguous []Ambiguity `json:"ambiguous,omitempty"` // the outcomes shared by several hypotheses
+	Unused    []Outcome   `json:"unused,omitempty"`    // the outcomes that no hypothesis produces
+	Unweighed []int       `json:"unweighed,omitempty"` // the coins that are never weighed
+}
+
+func (o Outcome) String() string {
+	b := make([]byte, len(o))
+	for i, w := range o {
+		switch w {
+		case Light:
+			b[i] = 'L'
+		case Equal:
+			b[i] = 'E'
+		case Heavy:
+			b[i] = 'H'
+		default:
+			b[i] = '?'
+		}
+	}
+	return string(b)
+}
+
+// Parse a string of L, E and H into an outcome.
+func ParseOutcome(s string) (Outcome, error) {
+	o := make(Outcome, len(s))
+	for i, c := range strings.ToUpper(s) {
+		switch c {
+		case 'L':
+			o[i] = Light
+		case 'E':
+			o[i] = Equal
+		case 'H':
+			o[i] = Heavy
+		default:
+			return nil, fmt.Errorf("invalid outcome: %s", s)
+		}
+	}
+	return o, nil
+}
+
+func (o Outcome) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.String())
+}
+
+func (o *Outcome) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	p, err := ParseOutcome(s)
+	if err == nil {
+		*o = p
+	}
+	return err
+}
+
+// Answer the outcome with the specified unsigned index between 0 and 3^K-1.
+func outcome(k int, u int) Outcome {
+	o := make(Outcome, k)
+	for j := k - 1; j >= 0; j-- {
+		o[j] = Weight(u % 3)
+		u /= 3
+	}
+	return o
+}
+
+// Diagnose the weighings of the receiver by weighing every hypothesis about the
+// counterfeit coin and its relative weight, including every permitted placement of
+// lies, and reporting the outcomes that are shared by hypotheses, the outcomes
+// that are never produced and the coins that are never weighed.
+//
+// An error is only answered if the weighings can't be weighed at all.
+func (s *Solution) Diagnose() (*Diagnosis, error) {
+	clone := s.Clone()
+	clone.reset()
+
+	if err := clone.checkWeighings(); err != nil {
+		return nil, err
+	}
+
+	k := len(clone.Weighings)
+	n := clone.CoinCount()
+	z := clone.GetZeroCoin()
+	placements := LiePlacements(k, clone.Lies())
+
+	hypotheses := make([][]Counterfeit, pow3(k))
+	for i := z; i < z+n; i++ {
+		for _, w := range []Weight{Light, Heavy} {
+			for _, lies := range placements {
+				u := index(clone.weigh(clone.oracle(i, w, lies))) + half(k)
+				h := hypotheses[u]
+				if len(h) > 0 && h[len(h)-1] == (Counterfeit{Coin: i, Weight: w}) {
+					continue
+				}
+				hypotheses[u] = append(h, Counterfeit{Coin: i, Weight: w})
+			}
+		}
+	}
+
+	d := &Diagnosis{
+		Ambiguous: []Ambiguity{},
+		Unused:    []Outcome{},
+		Unweighed: []int{},
+	}
+	for u, h := range hypotheses {
+		if len(h) == 0 {
+			d.Unused = append(d.Unused, outcome(k, u))
+			continue
+		}
+		ambiguous := false
+		for _, e := range h[1:] {
+			// a coin with both weights is only ambiguous if the weight must be found
+			if e.Coin != h[0].Coin || !clone.OddCoinOnly() {
+				ambiguous = true
+			}
+		}
+		if ambiguous {
+			d.Ambiguous = append(d.Ambiguous, Ambiguity{
+				Outcome:    outcome(k, u),
+				Hypotheses: h,
+			})
+		}
+	}
+
+	weighed := make([]bool, n+1)
+	for _, w := range clone.Weighings {
+		for _, c := range w.Both().AsCoins(z) {
+			weighed[c-z] = true
+		}
+	}
+	for i := z; i < z+n; i++ {
+		if !weighed[i-z] {
+			d.Unweighed = append(d.Unweighed, i)
+		}
+	}
+
+	d.Valid = len(d.Ambiguous) == 0
+	return d, nil
+}
@@ -0,0 +1,71 @@
+package lib
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDiagnoseValid(t *testing.T) {
+	d, err := load(t, "canonical.json").Diagnose()
+	if err != nil {
+		t.Fatalf("diagnose: %v", err)
+	}
+	if !d.Valid || len(d.Ambiguous) != 0 || len(d.Unweighed) != 0 {
+		t.Fatalf("unexpected diagnosis: %v", d)
+	}
+	if len(d.Unused) != 3 {
+		t.Fatalf("assertion failed: was: %d expected: %d", len(d.Unused), 3)
+	}
+}
+
+func TestDiagnoseTooFewCoins(t *testing.T) {
+	d, err := load(t, filepath.Join("invalid", "too-few-coins.json")).Diagnose()
+	if err != nil {
+		t.Fatalf("diagnose: %v", err)
+	}
+	if d.Valid || len(d.Ambiguous) == 0 {
+		t.Fatalf("unexpected diagnosis: %v", d)
+	}
+	for _, a := range d.Ambiguous {
+		if len(a.Hypotheses) < 2 {
+			t.Fatalf("unexpected ambiguity: %v", a)
+		}
+	}
+}
+
+func TestParseOutcome(t *testing.T) {
+	o, err := ParseOutcome("LEH")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if o.String() != "LEH" || o[0] != Light || o[1] != Equal || o[2] != Heavy {
+		t.Fatalf("assertion failed: was: %v expected: %v", o, "LEH")
+	}
+	if _, err := ParseOutcome("LXH"); err == nil {
+		t.Fatalf("expected parse to fail")
+	}
+}
+
+func TestDecideOutcome(t *testing.T) {
+	r, err := load(t, "canonical.json").Reverse()
+	if err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	d, err := r.Diagnose()
+	if err != nil {
+		t.Fatalf("diagnose: %v", err)
+	}
+	for _, o := range d.Unused {
+		if _, _, err := r.DecideOutcome(o); err == nil {
+			t.Fatalf("expected outcome %v to be inconsistent", o)
+		}
+	}
+	for i := 0; i < 12; i++ {
+		for _, w := range []Weight{Light, Heavy} {
+			o := Outcome(r.weigh(NewOracle(i+1, w, ONE_BASED)))
+			if c, cw, err := r.DecideOutcome(o); err != nil || c != i+1 || cw != w {
+				t.Fatalf("assertion failed: was: %d, %v, %v expected: %d, %v", c, cw, err, i+1, w)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package lib
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Dot renders the decision process of the solution as a Graphviz DOT graph: a complete
+// ternary tree with one level for each weighing and one leaf for each outcome. Each leaf
+// is labelled with the coin and relative weight that the outcome implies or marked
+// impossible if no counterfeit coin can produce the outcome.
+func (s *Solution) Dot() (string, error) {
+	var r *Solution
+	var err error
+	if s.flags&REVERSED == 0 {
+		if r, err = s.Reverse(); err != nil {
+			return "", err
+		}
+	} else {
+		r = s
+	}
+
+	z := r.GetZeroCoin()
+	k := len(r.Weighings)
+	results := make([]Weight, k)
+	b := &bytes.Buffer{}
+
+	fmt.Fprintf(b, "digraph decision {\n")
+	fmt.Fprintf(b, "\tnode [shape=box];\n")
+
+	var build func(id string, depth int)
+	build = func(id string, depth int) {
+		if depth == k {
+			if !r.possible(results) {
+				fmt.Fprintf(b, "\t%s [label=\"%v\\nimpossible\", style=dashed, fontcolor=gray];\n", id, Outcome(results))
+				return
+			}
+			coin, weight, _ := r.lookup(results)
+			verdict := weight.String()
+			if weight == Equal {
+				verdict = "light or heavy"
+			}
+			fmt.Fprintf(b, "\t%s [label=\"%v\\n%d %s\", shape=ellipse];\n", id, Outcome(results), coin, verdict)
+			return
+		}
+		w := r.Weighings[depth]
+		fmt.Fprintf(b, "\t%s [label=\"%v v %v\"];\n", id, w.Left().AsCoins(z), w.Right().AsCoins(z))
+		for _, result := range []Weight{Light, Equal, Heavy} {
+			results[depth] = result
+			child := id + Outcome{result}.String()
+			build(child, depth+1)
+			fmt.Fprintf(b, "\t%s -> %s [label=\"%v\"];\n", id, child, Outcome{result})
+		}
+	}
+	build("n", 0)
+
+	fmt.Fprintf(b, "}\n")
+	return b.String(), nil
+}
+
+// IncidenceDot renders the incidence of coins and weighings as a Graphviz DOT graph. Each
+// coin is joined to the weighings in which it is placed, with a solid edge for the left pan
+// and a dashed edge for the right pan. If the solution has groupings, the singletons, pairs
+// and triples are coloured differently.
+func (s *Solution) IncidenceDot() (string, error) {
+	if err := s.checkWeighings(); err != nil {
+		return "", err
+	}
+
+	z := s.GetZeroCoin()
+	n := s.CoinCount()
+	colours := map[int]string{}
+	// the groupings of a clone, since Groupings marks an invalid receiver as such
+	if g, err := s.Clone().Groupings(); err == nil {
+		for _, c := range g.Unique.AsCoins(z) {
+			colours[c] = "lightblue"
+		}
+		for _, p := range g.Pairs {
+			for _, c := range p.AsCoins(z) {
+				colours[c] = "palegreen"
+			}
+		}
+		for _, c := range g.Triples.AsCoins(z) {
+			colours[c] = "salmon"
+		}
+	}
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "graph incidence {\n")
+	for j, _ := range s.Weighings {
+		fmt.Fprintf(b, "\tw%d [label=\"weighing %d\", shape=box];\n", j, j+1)
+	}
+	for i := z; i < z+n; i++ {
+		if colour, ok := colours[i]; ok {
+			fmt.Fprintf(b, "\tc%d [label=\"%d\", style=filled, fillcolor=%s];\n", i, i, colour)
+		} else {
+			fmt.Fprintf(b, "\tc%d [label=\"%d\"];\n", i, i)
+		}
+	}
+	if g, reference := s.GenuineCoin(); reference {
+		fmt.Fprintf(b, "\tc%d [label=\"%d (genuine)\", shape=doublecircle];\n", g, g)
+	}
+	for j, w := range s.Weighings {
+		for _, c := range w.Left().AsCoins(z) {
+			fmt.Fprintf(b, "\tc%d -- w%d;\n", c, j)
+		}
+		for _, c := range w.Right().AsCoins(z) {
+			fmt.Fprintf(b, "\tc%d -- w%d [style=dashed];\n", c, j)
+		}
+	}
+	fmt.Fprintf(b, "}\n")
+	return b.String(), nil
+}
@@ -3,22 +3,44 @@ package lib
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"sort"
 )
 
 // A simple JSON encoding of data that has a richer structure internally.
 type encoding struct {
-	Weighings *[3][2][]int `json:"weighings,omitempty"`
-	Unique    *[]int       `json:"unique,omitempty"`
-	Pairs     *[3][2]int   `json:"pairs,omitempty"`
-	Triples   *[]int       `json:"triples,omitempty"`
-	Structure *[3]string   `json:"structure,omitempty"`
-	ZeroCoin  *int         `json:"zero-coin,omitempty"`
-	Flip      *int         `json:"flip,omitempty"`
-	S         *uint        `json:"S,omitempty"`
-	F         *uint        `json:"F,omitempty"`
-	P         []int        `json:"P,omitempty"`
-	N         *uint        `json:"N,omitempty"`
+	Weighings   *[][2][]int  `json:"weighings,omitempty"`
+	Unique      *[]int       `json:"unique,omitempty"`
+	Pairs       *[3][]int    `json:"pairs,omitempty"`
+	Triples     *[]int       `json:"triples,omitempty"`
+	Structure   *[3]string   `json:"structure,omitempty"`
+	Composition *[][2]string `json:"composition,omitempty"`
+	ZeroCoin    *int         `json:"zero-coin,omitempty"`
+	CoinCount   *int         `json:"coin-count,omitempty"`
+	Genuine     *int         `json:"genuine,omitempty"`
+	OddOnly     *bool        `json:"odd-coin-only,omitempty"`
+	Lies        *int         `json:"lies,omitempty"`
+	Numeric     *bool        `json:"numeric,omitempty"`
+	Flip        *int         `json:"flip,omitempty"`
+	S           *uint        `json:"S,omitempty"`
+	F           *uint        `json:"F,omitempty"`
+	P           []int        `json:"P,omitempty"`
+	N           *uint        `json:"N,omitempty"`
+	Orbit       *big.Int     `json:"orbit,omitempty"`
+}
+
+// Answer the part of the encoding that defines the problem, as opposed to the
+// analysis of the solution.
+func (e *encoding) problem() encoding {
+	return encoding{
+		ZeroCoin:  e.ZeroCoin,
+		Flip:      e.Flip,
+		CoinCount: e.CoinCount,
+		Genuine:   e.Genuine,
+		OddOnly:   e.OddOnly,
+		Lies:      e.Lies,
+		Numeric:   e.Numeric,
+	}
 }
 
 // Convert the solution to its JSON representation.
@@ -31,7 +53,7 @@ func (s *Solution) String() string {
 // Encode the rich structure into the simple JSON encoding.
 func (s *Solution) Encode() {
 	z := s.GetZeroCoin()
-	tmp := [3][2][]int{}
+	tmp := make([][2][]int, len(s.Weighings))
 	s.encoding.ZeroCoin = pi(z)
 	if *s.encoding.ZeroCoin == 1 {
 		s.encoding.ZeroCoin = nil
@@ -39,7 +61,7 @@ func (s *Solution) Encode() {
 	s.encoding.Weighings = &tmp
 	for i, w := range s.Weighings {
 		for j, p := range w.Pans() {
-			s.encoding.Weighings[i][j] = p.AsCoins(z)
+			tmp[i][j] = p.AsCoins(z)
 		}
 	}
 	if s.Unique != nil {
@@ -51,10 +73,12 @@ func (s *Solution) Encode() {
 		s.encoding.Triples = &tmp
 	}
 	if s.Unique != nil {
-		tmp := [3][2]int{}
+		tmp := [3][]int{}
 		for i, _ := range tmp {
 			if s.Pairs[i] != nil {
-				copy(tmp[i][0:], s.Pairs[i].AsCoins(z))
+				tmp[i] = s.Pairs[i].AsCoins(z)
+			} else {
+				tmp[i] = []int{}
 			}
 		}
 		s.encoding.Pairs = &tmp
@@ -76,6 +100,7 @@ func (s *Solution) Encode() {
 func (s *Solution) DecodeJSON() {
 	z := s.GetZeroCoin()
 	if s.encoding.Weighings != nil {
+		s.Weighings = make([]Weighing, len(*s.encoding.Weighings))
 		for i, w := range *s.encoding.Weighings {
 			s.Weighings[i] = NewWeighing(NewCoinSet(w[0], z), NewCoinSet(w[1], z))
 		}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 )
 
-// Tabulate the singletons, pairs and triples of the solution.
+// Tabulate the singletons, pairs and triples of the solution. The coins of the 12 coins problem
+// must consist of 3 singletons, 3 pairs and 3 triples. The coins of other problems, such as the
+// 13 coins problem with a reference coin, may be grouped differently and the
+// reference coin itself is excluded from the groupings.
 func (s *Solution) Groupings() (*Solution, error) {
 
 	var clone *Solution
@@ -20,9 +23,19 @@ func (s *Solution) Groupings() (*Solution, error) {
 
 	clone.reset()
 
-	a := clone.Weighings[0].Both()
-	b := clone.Weighings[1].Both()
-	c := clone.Weighings[2].Both()
+	if len(clone.Weighings) != 3 {
+		s.flags = INVALID
+		return s, fmt.Errorf("groupings are only defined for solutions with 3 weighings: %d", len(clone.Weighings))
+	}
+
+	genuine := NewCoinSet([]int{}, 0)
+	if g, reference := clone.GenuineCoin(); reference {
+		genuine = NewCoinSet([]int{g}, clone.GetZeroCoin())
+	}
+
+	a := clone.Weighings[0].Both().Complement(genuine)
+	b := clone.Weighings[1].Both().Complement(genuine)
+	c := clone.Weighings[2].Both().Complement(genuine)
 
 	ab := a.Intersection(b)
 	bc := b.Intersection(c)
@@ -33,7 +46,7 @@ func (s *Solution) Groupings() (*Solution, error) {
 	singletons := a.Complement(b.Union(c)).Union(b.Complement(a.Union(c))).Union(c.Complement(a.Union(b)))
 	pairs := all.Complement(triples).Complement(singletons)
 
-	if triples.Size() != 3 || singletons.Size() != 3 || pairs.Size() != 6 {
+	if clone.isTwelveCoins() && (triples.Size() != 3 || singletons.Size() != 3 || pairs.Size() != 6) {
 		s.flags = INVALID
 		return s, fmt.Errorf("invalid grouping sizes: %v, %v, %v", triples, pairs, singletons)
 	}
@@ -0,0 +1,301 @@
+package lib
+
+import (
+	"fmt"
+	"sort"
+)
+
+// An Invariant is one of the assumed truths about valid solutions of the 12 coins problem
+// that are listed in structure.go.
+type Invariant struct {
+	Name  string
+	check func(solutions []*Solution) []Counterexample
+}
+
+// A Counterexample is a solution that violates an invariant and the reason it does so.
+type Counterexample struct {
+	Solution *Solution `json:"solution"`
+	Reason   string    `json:"reason"`
+}
+
+// An InvariantReport records the result of checking an invariant against a set of solutions.
+type InvariantReport struct {
+	Invariant       string           `json:"invariant"`
+	Checked         int              `json:"checked"`
+	Pass            bool             `json:"pass"`
+	Counterexamples []Counterexample `json:"counterexamples,omitempty"`
+}
+
+// The assumed truths of structure.go, in the order they are listed there. Each invariant
+// is unchanged by relabeling the coins and by the order of the pans, so it is enough to
+// check the solutions that Search finds.
+var Invariants = []Invariant{
+	{"no coin may appear twice in the same weighing", each(noCoinTwice)},
+	{"every coin must be weighed at least once", each(everyCoinWeighed)},
+	{"every solution has exactly 3 triples", each(countAppearances(3, 3))},
+	{"every solution has exactly 3 singletons", each(countAppearances(1, 3))},
+	{"every solution has exactly 3 disjoint pairs", each(threePairs)},
+	{"every solution has 3 weighings of 4 coins on each pan", each(fourCoinsPerPan)},
+	{"every coin appears in exactly 2 weighings", each(twoWeighings)},
+	{"every singleton appears in its own weighing", each(singletonsApart)},
+	{"every pair is split by one weighing and is joined by another", each(splitAndJoined)},
+	{"at most one weighing has all 3 triples on the one side", each(triplesOnOneSide)},
+	{"there are 5 solutions with distinct structures", fiveStructures},
+	{"every other valid solution is obtained from the 5 structures", each(numbered)},
+}
+
+// Check each invariant against the solutions.
+func CheckInvariants(solutions []*Solution) []InvariantReport {
+	reports := make([]InvariantReport, len(Invariants))
+	for i, inv := range Invariants {
+		ce := inv.check(solutions)
+		reports[i] = InvariantReport{
+			Invariant:       inv.Name,
+			Checked:         len(solutions),
+			Pass:            len(ce) == 0,
+			Counterexamples: ce,
+		}
+	}
+	return reports
+}
+
+// Adapt a check of a single solution into a check of a set of solutions.
+func each(check func(s *Solution) error) func([]*Solution) []Counterexample {
+	return func(solutions []*Solution) []Counterexample {
+		ce := []Counterexample{}
+		for _, s := range solutions {
+			if err := check(s); err != nil {
+				ce = append(ce, Counterexample{Solution: s, Reason: err.Error()})
+			}
+		}
+		return ce
+	}
+}
+
+// Answer, for each coin, the pan it occupies in each weighing: 0 for left, 1 for right and
+// -1 if the coin is not weighed. Coins are indexed from 0.
+func placements(s *Solution) [][]int {
+	z := s.GetZeroCoin()
+	p := make([][]int, s.CoinCount())
+	for i, _ := range p {
+		p[i] = make([]int, len(s.Weighings))
+		for j, _ := range p[i] {
+			p[i][j] = -1
+		}
+	}
+	for j, w := range s.Weighings {
+		for pan, coins := range w.Pans() {
+			for _, c := range coins.AsCoins(z) {
+				if c-z >= 0 && c-z < len(p) {
+					p[c-z][j] = pan
+				}
+			}
+		}
+	}
+	return p
+}
+
+// Answer the weighings in which a coin appears.
+func appearances(pans []int) []int {
+	a := []int{}
+	for j, pan := range pans {
+		if pan >= 0 {
+			a = append(a, j)
+		}
+	}
+	return a
+}
+
+func noCoinTwice(s *Solution) error {
+	z := s.GetZeroCoin()
+	for j, w := range s.Weighings {
+		if both := w.Left().Intersection(w.Right()); both.Size() != 0 {
+			return fmt.Errorf("weighing %d has coins %v on both pans", j+1, both.AsCoins(z))
+		}
+	}
+	return nil
+}
+
+func everyCoinWeighed(s *Solution) error {
+	for i, pans := range placements(s) {
+		if len(appearances(pans)) == 0 {
+			return fmt.Errorf("coin %d is never weighed", i+s.GetZeroCoin())
+		}
+	}
+	return nil
+}
+
+func countAppearances(times int, expected int) func(s *Solution) error {
+	return func(s *Solution) error {
+		n := 0
+		for _, pans := range placements(s) {
+			if len(appearances(pans)) == times {
+				n++
+			}
+		}
+		if n != expected {
+			return fmt.Errorf("%d coins appear in %d weighings", n, times)
+		}
+		return nil
+	}
+}
+
+// Answer the pairs of the solution: the coins that appear in exactly 2 weighings, keyed
+// by the weighings they appear in.
+func pairsOf(s *Solution) map[[2]int][]int {
+	pairs := map[[2]int][]int{}
+	for i, pans := range placements(s) {
+		if a := appearances(pans); len(a) == 2 {
+			key := [2]int{a[0], a[1]}
+			pairs[key] = append(pairs[key], i)
+		}
+	}
+	return pairs
+}
+
+func threePairs(s *Solution) error {
+	pairs := pairsOf(s)
+	if len(pairs) != 3 {
+		return fmt.Errorf("the coins that appear in 2 weighings form %d groups", len(pairs))
+	}
+	for key, coins := range pairs {
+		if len(coins) != 2 {
+			return fmt.Errorf("%d coins appear in weighings %d and %d", len(coins), key[0]+1, key[1]+1)
+		}
+	}
+	return nil
+}
+
+func fourCoinsPerPan(s *Solution) error {
+	if len(s.Weighings) != 3 {
+		return fmt.Errorf("%d weighings", len(s.Weighings))
+	}
+	for j, w := range s.Weighings {
+		if w.Left().Size() != 4 || w.Right().Size() != 4 {
+			return fmt.Errorf("weighing %d has %d and %d coins", j+1, w.Left().Size(), w.Right().Size())
+		}
+	}
+	return nil
+}
+
+func twoWeighings(s *Solution) error {
+	for i, pans := range placements(s) {
+		if n := len(appearances(pans)); n != 2 {
+			return fmt.Errorf("coin %d appears in %d weighings", i+s.GetZeroCoin(), n)
+		}
+	}
+	return nil
+}
+
+func singletonsApart(s *Solution) error {
+	seen := map[int]int{}
+	for i, pans := range placements(s) {
+		if a := appearances(pans); len(a) == 1 {
+			if other, ok := seen[a[0]]; ok {
+				return fmt.Errorf("coins %d and %d are the only appearance of each in weighing %d", other+s.GetZeroCoin(), i+s.GetZeroCoin(), a[0]+1)
+			}
+			seen[a[0]] = i
+		}
+	}
+	return nil
+}
+
+func splitAndJoined(s *Solution) error {
+	p := placements(s)
+	for key, coins := range pairsOf(s) {
+		if len(coins) != 2 {
+			return fmt.Errorf("%d coins appear in weighings %d and %d", len(coins), key[0]+1, key[1]+1)
+		}
+		split := 0
+		for _, j := range key {
+			if p[coins[0]][j] != p[coins[1]][j] {
+				split++
+			}
+		}
+		if split != 1 {
+			return fmt.Errorf("the pair %d, %d is split by %d weighings", coins[0]+s.GetZeroCoin(), coins[1]+s.GetZeroCoin(), split)
+		}
+	}
+	return nil
+}
+
+func triplesOnOneSide(s *Solution) error {
+	p := placements(s)
+	oneSide := []int{}
+	for j, _ := range s.Weighings {
+		left, right := 0, 0
+		for _, pans := range p {
+			if len(appearances(pans)) != len(s.Weighings) {
+				continue
+			}
+			if pans[j] == 0 {
+				left++
+			} else {
+				right++
+			}
+		}
+		if left == 3 || right == 3 {
+			oneSide = append(oneSide, j+1)
+		}
+	}
+	if len(oneSide) > 1 {
+		return fmt.Errorf("weighings %v have all 3 triples on the one side", oneSide)
+	}
+	return nil
+}
+
+func fiveStructures(solutions []*Solution) []Counterexample {
+	names := map[string]*Solution{}
+	for _, s := range solutions {
+		a, err := s.AnalyseStructure()
+		if err != nil {
+			return []Counterexample{{Solution: s, Reason: fmt.Sprintf("structure: %v", err)}}
+		}
+		if _, ok := names[StructureName(*a.encoding.S)]; !ok {
+			names[StructureName(*a.encoding.S)] = s
+		}
+	}
+	found := []string{}
+	for name, _ := range names {
+		found = append(found, name)
+	}
+	sort.Strings(found)
+	ce := []Counterexample{}
+	for _, name := range found {
+		switch name {
+		case "ppp", "qpp", "prs", "prt", "qrs":
+		default:
+			ce = append(ce, Counterexample{Solution: names[name], Reason: fmt.Sprintf("unexpected structure %s", name)})
+		}
+	}
+	if len(found) != 5 && len(ce) == 0 {
+		ce = append(ce, Counterexample{Reason: fmt.Sprintf("found %d structures: %v", len(found), found)})
+	}
+	return ce
+}
+
+// A solution is obtained from the 5 structures if the solution decoded from its number
+// places the same coins on each pan.
+func numbered(s *Solution) error {
+	n, err := s.N()
+	if err != nil {
+		return fmt.Errorf("N: %v", err)
+	}
+	d, err := DecodeSolution(n)
+	if err != nil {
+		return fmt.Errorf("decode: %d: %v", n, err)
+	}
+	z := s.GetZeroCoin()
+	for j, w := range s.Weighings {
+		for pan, coins := range w.Pans() {
+			a := coins.AsCoins(z)
+			b := d.Weighings[j].Pan(pan).AsCoins(z)
+			sort.Ints(a)
+			sort.Ints(b)
+			if fmt.Sprint(a) != fmt.Sprint(b) {
+				return fmt.Errorf("the solution decoded from its number, %d, places other coins on the pans of weighing %d", n, j+1)
+			}
+		}
+	}
+	return nil
+}
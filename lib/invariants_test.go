@@ -0,0 +1,45 @@
+package lib
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInvariants(t *testing.T) {
+	solutions := []*Solution{}
+	Search(12, 3, func(s *Solution) {
+		solutions = append(solutions, s)
+	})
+	failures := map[string]int{
+		"every coin appears in exactly 2 weighings":                    38,
+		"every other valid solution is obtained from the 5 structures": 16,
+	}
+	reports := CheckInvariants(solutions)
+	if len(reports) != len(Invariants) {
+		t.Fatalf("assertion failed: was: %d expected: %d", len(reports), len(Invariants))
+	}
+	for _, r := range reports {
+		if r.Checked != 38 {
+			t.Fatalf("assertion failed: was: %d expected: %d", r.Checked, 38)
+		}
+		expected := failures[r.Invariant]
+		if len(r.Counterexamples) != expected || r.Pass != (expected == 0) {
+			t.Fatalf("%s: assertion failed: was: %d expected: %d", r.Invariant, len(r.Counterexamples), expected)
+		}
+	}
+}
+
+func TestInvariantCounterexample(t *testing.T) {
+	// the pair 1, 2 is split by both weighings.
+	s := &Solution{}
+	if err := json.Unmarshal([]byte(`{"weighings":[[[1],[2]],[[1],[2]]]}`), s); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	s.DecodeJSON()
+	if err := splitAndJoined(s); err == nil {
+		t.Fatalf("expected a counterexample: %v", s)
+	}
+	if err := twoWeighings(s); err != nil {
+		t.Fatalf("unexpected counterexample: %v", err)
+	}
+}
@@ -39,55 +39,100 @@ type Rebaseable interface {
 // a collection weighs less, the same as or more than the right collection.
 //
 // Each slice can contain the numbers 0, 11. A number that appears in one slice
-// may not appear in another slice.
+// may not appear in another slice. Both slices must contain the same number of coins.
 type Scale interface {
 	Rebaseable
 	Weigh(a []int, b []int) Weight
 }
 
 type Oracle struct {
-	coin     int
-	weight   Weight
-	attempts int
-	err      error
-	zeroCoin int
+	coin         int
+	weight       Weight
+	attempts     int
+	err          error
+	zeroCoin     int
+	coins        int
+	maxWeighings int
+	reference    bool
+	unbalanced   bool
 }
 
 type Candidate func(Scale) (int, Weight)
 
+// Answer the largest number of coins for which the counterfeit coin and its
+// relative weight can be found in the specified number of weighings, (3^K-3)/2.
+func MaxCoins(weighings int) int {
+	return (pow3(weighings) - 3) / 2
+}
+
+// Answer the largest number of coins for which the counterfeit coin and its relative
+// weight can be found in the specified number of weighings with the help of an
+// additional coin that is known to be genuine, (3^K-1)/2.
+func MaxReferenceCoins(weighings int) int {
+	return half(weighings)
+}
+
+// Answer the largest number of coins for which the counterfeit coin, but not necessarily its
+// relative weight, can be found in the specified number of weighings. This is also (3^K-1)/2
+// because one coin more than MaxCoins can be left off the scale: if every weighing balances,
+// it is the counterfeit coin, although its relative weight is unknown.
+func MaxOddCoins(weighings int) int {
+	return half(weighings)
+}
+
+// Create an oracle for the 12 coins problem which allows 3 weighings.
 func NewOracle(coin int, w Weight, zeroCoin int) *Oracle {
+	return NewOracleN(12, 3, coin, w, zeroCoin)
+}
+
+// Create an oracle for a problem of the specified number of coins that allows at
+// most maxWeighings uses of the scale.
+func NewOracleN(coins int, maxWeighings int, coin int, w Weight, zeroCoin int) *Oracle {
 	return &Oracle{
-		coin:     coin,
-		weight:   w,
-		zeroCoin: zeroCoin,
+		coin:         coin,
+		weight:       w,
+		zeroCoin:     zeroCoin,
+		coins:        coins,
+		maxWeighings: maxWeighings,
 	}
 }
 
+// Create an oracle for a problem of the specified number of coins that also
+// has a reference coin which is known to be genuine. The reference coin is
+// numbered zeroCoin+coins and may be placed on either pan.
+func NewReferenceOracle(coins int, maxWeighings int, coin int, w Weight, zeroCoin int) *Oracle {
+	o := NewOracleN(coins, maxWeighings, coin, w, zeroCoin)
+	o.reference = true
+	return o
+}
+
 func (o *Oracle) fail(err error) {
 	o.err = err
 	panic(err)
 }
 
 func (o *Oracle) check(a []int, b []int) {
-	seen := [12]bool{}
-	if o.attempts == 3 {
+	limit := o.coins
+	if o.reference {
+		limit += 1
+	}
+	seen := make([]bool, limit)
+	if o.attempts == o.maxWeighings {
 		o.fail(fmt.Errorf("too many attempts to use the scale!"))
 	}
-	for _, e := range a {
-		if e < o.zeroCoin || e > 11+o.zeroCoin {
-			o.fail(fmt.Errorf("invalid coin: %d", e))
-		}
-		if seen[e-o.zeroCoin] {
-			o.fail(fmt.Errorf("duplicate detected: %d", e))
-		} else {
-			seen[e-o.zeroCoin] = true
-		}
+	if len(a) != len(b) && !o.unbalanced {
+		o.fail(fmt.Errorf("unbalanced weighing: %d coins vs %d coins", len(a), len(b)))
 	}
-	for _, e := range b {
-		if seen[e-o.zeroCoin] {
-			o.fail(fmt.Errorf("duplicate detected: %d", e))
-		} else {
-			seen[e-o.zeroCoin] = true
+	for _, pan := range [][]int{a, b} {
+		for _, e := range pan {
+			if e < o.zeroCoin || e >= limit+o.zeroCoin {
+				o.fail(fmt.Errorf("invalid coin: %d", e))
+			}
+			if seen[e-o.zeroCoin] {
+				o.fail(fmt.Errorf("duplicate detected: %d", e))
+			} else {
+				seen[e-o.zeroCoin] = true
+			}
 		}
 	}
 }
@@ -105,6 +150,11 @@ func (o *Oracle) GetZeroCoin() int {
 	return o.zeroCoin
 }
 
+// Reveal the counterfeit coin and its relative weight.
+func (o *Oracle) Reveal() (int, Weight) {
+	return o.coin, o.weight
+}
+
 // The oracle implements the Scale interface and happens to know which
 // coin is the different coin.
 func (o *Oracle) Weigh(a []int, b []int) Weight {
@@ -128,18 +178,46 @@ func (o *Oracle) Weigh(a []int, b []int) Weight {
 
 // test checks whether decide answers the right coin for a given coin and relative weight
 func Test(i int, w Weight, zeroCoin int, p Candidate) error {
-	oracle := NewOracle(i, w, zeroCoin)
+	return TestN(12, 3, i, w, zeroCoin, p)
+}
+
+// TestN checks whether decide answers the right coin for a given coin and relative weight
+// of a problem with the specified number of coins and weighings.
+func TestN(coins int, weighings int, i int, w Weight, zeroCoin int, p Candidate) error {
+	o := NewOracleN(coins, weighings, i, w, zeroCoin)
+	return test(o, o, p, false)
+}
+
+// TestOddCoin checks whether decide answers the right coin for a given coin and relative weight
+// of a problem with the specified number of coins and weighings. The relative weight answered
+// by decide is ignored.
+func TestOddCoin(coins int, weighings int, i int, w Weight, zeroCoin int, p Candidate) error {
+	o := NewOracleN(coins, weighings, i, w, zeroCoin)
+	return test(o, o, p, true)
+}
+
+// TestReference checks whether decide answers the right coin for a given coin and relative weight
+// of a problem with the specified number of coins and weighings and a reference coin.
+func TestReference(coins int, weighings int, i int, w Weight, zeroCoin int, p Candidate) error {
+	o := NewReferenceOracle(coins, weighings, i, w, zeroCoin)
+	return test(o, o, p, false)
+}
+
+// test checks whether decide, given the scale, answers the coin and, unless oddCoinOnly is true,
+// the relative weight known to the oracle. The scale is either the oracle itself or wraps it.
+func test(oracle *Oracle, scale Scale, p Candidate, oddCoinOnly bool) error {
+	w := oracle.weight
 	func() {
 		defer func() {
 			if err := recover(); err != nil {
 				oracle.err = err.(error)
 			}
 		}()
-		ri, rw := p(oracle)
+		ri, rw := p(scale)
 		if ri != oracle.coin {
 			panic(fmt.Errorf("decide chose coin %d", ri))
 		}
-		if rw != w {
+		if rw != w && !oddCoinOnly {
 			panic(fmt.Errorf("decide chose weight %v", rw))
 		}
 	}()
@@ -147,10 +225,52 @@ func Test(i int, w Weight, zeroCoin int, p Candidate) error {
 }
 
 func TestAll(p Candidate) []error {
+	return TestAllN(12, 3, p)
+}
+
+// TestAllN tests the candidate against all possibilities of a problem with the specified
+// number of coins and weighings. The problem must be solvable, that is: 3 <= coins <= (3^K-3)/2.
+func TestAllN(coins int, weighings int, p Candidate) []error {
+	if coins < 3 || coins > MaxCoins(weighings) {
+		return []error{fmt.Errorf("unsolvable: %d coins cannot be decided in %d weighings\n", coins, weighings)}
+	}
+	return testAll(coins, func(i int, w Weight) error {
+		return TestN(coins, weighings, i, w, 0, p)
+	})
+}
+
+// TestAllReference tests the candidate against all possibilities of a problem with the specified
+// number of coins and weighings and an additional reference coin which is known to be genuine.
+// The problem must be solvable, that is: 1 <= coins <= (3^K-1)/2.
+func TestAllReference(coins int, weighings int, p Candidate) []error {
+	if coins < 1 || coins > MaxReferenceCoins(weighings) {
+		return []error{fmt.Errorf("unsolvable: %d coins cannot be decided in %d weighings with a reference coin\n", coins, weighings)}
+	}
+	return testAll(coins, func(i int, w Weight) error {
+		return TestReference(coins, weighings, i, w, 0, p)
+	})
+}
+
+// TestAllOddCoin tests the candidate against all possibilities of a problem with the specified
+// number of coins and weighings but only requires the candidate to find the counterfeit coin,
+// not its relative weight. The problem must be solvable, that is: coins = 1 or
+// 3 <= coins <= (3^K-1)/2. 2 coins are never enough because, without a reference coin,
+// the only weighing cannot tell a light first coin from a heavy second coin.
+func TestAllOddCoin(coins int, weighings int, p Candidate) []error {
+	if coins < 1 || coins == 2 || coins > MaxOddCoins(weighings) {
+		return []error{fmt.Errorf("unsolvable: the odd coin of %d coins cannot be found in %d weighings\n", coins, weighings)}
+	}
+	return testAll(coins, func(i int, w Weight) error {
+		return TestOddCoin(coins, weighings, i, w, 0, p)
+	})
+}
+
+// testAll runs the test for each coin and relative weight and reports the failures.
+func testAll(coins int, test func(i int, w Weight) error) []error {
 	errors := []error{}
-	for i := 0; i < 12; i++ {
+	for i := 0; i < coins; i++ {
 		for _, w := range []Weight{Light, Heavy} {
-			if err := Test(i, w, 0, p); err != nil {
+			if err := test(i, w); err != nil {
 				errors = append(errors, fmt.Errorf("fail: for (%d, %v): %v\n", i, w, err))
 			}
 		}
@@ -0,0 +1,91 @@
+package lib
+
+import (
+	"testing"
+)
+
+// decides the 3 coins problem in 2 weighings
+func decide3(scale Scale) (int, Weight) {
+	scale.SetZeroCoin(0)
+	r := scale.Weigh([]int{0}, []int{1})
+	if r == Equal {
+		return 2, scale.Weigh([]int{0}, []int{2}).Invert()
+	}
+	if scale.Weigh([]int{0}, []int{2}) == Equal {
+		return 1, r.Invert()
+	}
+	return 0, r
+}
+
+func TestMaxCoins(t *testing.T) {
+	for k, e := range map[int]int{2: 3, 3: 12, 4: 39} {
+		if n := MaxCoins(k); n != e {
+			t.Fatalf("assertion failed: was: %d expected: %d", n, e)
+		}
+	}
+}
+
+func TestAllN3Coins(t *testing.T) {
+	if errors := TestAllN(3, 2, decide3); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+}
+
+func TestAllNTooFewWeighings(t *testing.T) {
+	if errors := TestAllN(3, 1, decide3); len(errors) != 1 {
+		t.Fatalf("expected exactly one failure: %v", errors)
+	}
+}
+
+func TestAllNTooManyAttempts(t *testing.T) {
+	errors := TestAllN(4, 3, func(scale Scale) (int, Weight) {
+		for {
+			scale.Weigh([]int{0}, []int{1})
+		}
+	})
+	if len(errors) != 8 {
+		t.Fatalf("expected 8 failures: was %d", len(errors))
+	}
+}
+
+func TestAllNUnbalanced(t *testing.T) {
+	errors := TestAllN(3, 2, func(scale Scale) (int, Weight) {
+		return 0, scale.Weigh([]int{0}, []int{})
+	})
+	if len(errors) != 6 {
+		t.Fatalf("expected 6 failures: was %d", len(errors))
+	}
+}
+
+func TestAllReferenceUnbalanced(t *testing.T) {
+	// 1 coin in 1 weighing requires the reference coin to balance the pans
+	errors := TestAllReference(1, 1, func(scale Scale) (int, Weight) {
+		return 0, scale.Weigh([]int{0}, []int{1})
+	})
+	if len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+}
+
+func TestAllOddCoinBounds(t *testing.T) {
+	// a candidate that only weighs coin 0 against coin 1 cannot find the odd coin of 2 coins.
+	decide2 := func(scale Scale) (int, Weight) {
+		if scale.Weigh([]int{0}, []int{1}) == Light {
+			return 0, Light
+		}
+		return 0, Heavy
+	}
+	for _, k := range []int{1, 2, 3} {
+		if errors := TestAllOddCoin(2, k, decide2); len(errors) != 1 {
+			t.Fatalf("expected 2 coins to be unsolvable in %d weighings: %v", k, errors)
+		}
+	}
+	if errors := TestAllOddCoin(1, 1, func(scale Scale) (int, Weight) { return 0, Equal }); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+	for k, e := range map[int]int{1: 1, 2: 4, 3: 13} {
+		if n := MaxOddCoins(k); n != e || n != MaxReferenceCoins(k) {
+			t.Fatalf("assertion failed: was: %d expected: %d", n, e)
+		}
+	}
+}
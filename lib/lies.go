@@ -0,0 +1,87 @@
+package lib
+
+import (
+	"fmt"
+)
+
+// A Lie causes one use of a scale to answer wrongly.
+type Lie struct {
+	Attempt int `json:"attempt"` // the use of the scale, counting from 0, that answers wrongly
+	Offset  int `json:"offset"`  // 1 or 2: the wrong answer is (answer + offset) mod 3
+}
+
+// A LyingOracle implements the Scale interface, happens to know which coin is the
+// different coin but answers some uses of the scale wrongly.
+type LyingOracle struct {
+	Oracle
+	lies []Lie
+}
+
+// Create an oracle that answers wrongly according to the specified lies.
+func NewLyingOracle(coins int, maxWeighings int, coin int, w Weight, zeroCoin int, lies []Lie) *LyingOracle {
+	return &LyingOracle{
+		Oracle: *NewOracleN(coins, maxWeighings, coin, w, zeroCoin),
+		lies:   lies,
+	}
+}
+
+// Weigh the coins, but lie if this use of the scale is one of the lies.
+func (o *LyingOracle) Weigh(a []int, b []int) Weight {
+	attempt := o.attempts
+	r := o.Oracle.Weigh(a, b)
+	for _, l := range o.lies {
+		if l.Attempt == attempt {
+			r = Weight((int(r) + l.Offset) % 3)
+		}
+	}
+	return r
+}
+
+// Answer every placement of at most maxLies lies amongst the specified number of weighings,
+// including the placement of no lies.
+func LiePlacements(weighings int, maxLies int) [][]Lie {
+	result := [][]Lie{}
+	var place func(next int, lies []Lie)
+	place = func(next int, lies []Lie) {
+		c := make([]Lie, len(lies))
+		copy(c, lies)
+		result = append(result, c)
+		if len(lies) == maxLies {
+			return
+		}
+		for a := next; a < weighings; a++ {
+			for _, offset := range []int{1, 2} {
+				place(a+1, append(lies, Lie{Attempt: a, Offset: offset}))
+			}
+		}
+	}
+	place(0, []Lie{})
+	return result
+}
+
+// TestLies checks whether decide answers the right coin for a given coin and relative weight
+// when the scale answers wrongly according to the specified lies.
+func TestLies(coins int, weighings int, i int, w Weight, zeroCoin int, lies []Lie, p Candidate) error {
+	o := NewLyingOracle(coins, weighings, i, w, zeroCoin, lies)
+	return test(&o.Oracle, o, p, false)
+}
+
+// TestAllLies tests the candidate against all possibilities of a problem with the specified
+// number of coins and weighings and every placement of at most maxLies wrong answers
+// by the scale.
+func TestAllLies(coins int, weighings int, maxLies int, p Candidate) []error {
+	placements := LiePlacements(weighings, maxLies)
+	if coins < 3 || 2*coins*len(placements) > pow3(weighings) {
+		return []error{fmt.Errorf("unsolvable: %d coins cannot be decided in %d weighings with %d lies\n", coins, weighings, maxLies)}
+	}
+	errors := []error{}
+	for _, lies := range placements {
+		errs := testAll(coins, func(i int, w Weight) error {
+			return TestLies(coins, weighings, i, w, 0, lies, p)
+		})
+		for _, e := range errs {
+			errors = append(errors, fmt.Errorf("lies: %v: %v", lies, e))
+		}
+	}
+	return errors
+}
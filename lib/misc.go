@@ -31,3 +31,27 @@ func fact(n int) int {
 		return n * fact(n-1)
 	}
 }
+
+// calculate 3 to the power of n
+func pow3(n int) int {
+	r := 1
+	for i := 0; i < n; i++ {
+		r *= 3
+	}
+	return r
+}
+
+// calculate (3^k-1)/2, the offset of the EEE...E outcome in a table of all outcomes of k weighings
+func half(k int) int {
+	return (pow3(k) - 1) / 2
+}
+
+// calculate the signed index of a sequence of weighing results which is
+// between -half(len(results)) and half(len(results)), inclusive.
+func index(results []Weight) int {
+	i := 0
+	for _, r := range results {
+		i = i*3 + int(r)
+	}
+	return i - half(len(results))
+}
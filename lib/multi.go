@@ -0,0 +1,216 @@
+package lib
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A Counterfeit identifies a counterfeit coin and its weight relative to a genuine coin.
+type Counterfeit struct {
+	Coin   int    `json:"coin"`
+	Weight Weight `json:"weight"`
+}
+
+// A MultiCandidate decides which coins of a problem with several counterfeit
+// coins are counterfeit and what the relative weight of each is.
+type MultiCandidate func(Scale) []Counterfeit
+
+// A MultiOracle implements the Scale interface and knows which coins are counterfeit and
+// by how much each deviates from the weight of a genuine coin.
+//
+// The oracle validates weighings like an Oracle but has no single counterfeit coin to
+// reveal, so it does not embed one.
+type MultiOracle struct {
+	oracle       Oracle
+	counterfeits []Counterfeit
+	deviations   []int
+}
+
+// Create an oracle for a problem of the specified number of coins, that allows
+// at most maxWeighings uses of the scale and has the specified counterfeit coins.
+// It is the oracle of NewMultiOracleWithDeviations in which every counterfeit coin
+// deviates by 1.
+func NewMultiOracle(coins int, maxWeighings int, counterfeits []Counterfeit, zeroCoin int) *MultiOracle {
+	deviations := make([]int, len(counterfeits))
+	for i, _ := range deviations {
+		deviations[i] = 1
+	}
+	return NewMultiOracleWithDeviations(coins, maxWeighings, counterfeits, deviations, zeroCoin)
+}
+
+// Create an oracle like NewMultiOracle in which the i'th counterfeit coin deviates from
+// the weight of a genuine coin by deviations[i], which must be positive.
+func NewMultiOracleWithDeviations(coins int, maxWeighings int, counterfeits []Counterfeit, deviations []int, zeroCoin int) *MultiOracle {
+	c := make([]Counterfeit, len(counterfeits))
+	copy(c, counterfeits)
+	d := make([]int, len(deviations))
+	copy(d, deviations)
+	return &MultiOracle{
+		oracle:       *NewOracleN(coins, maxWeighings, zeroCoin-1, Equal, zeroCoin),
+		counterfeits: c,
+		deviations:   d,
+	}
+}
+
+// All future weighings will used the specified coin as the zero coin.
+func (o *MultiOracle) SetZeroCoin(coin int) {
+	diff := coin - o.oracle.zeroCoin
+	for i, _ := range o.counterfeits {
+		o.counterfeits[i].Coin += diff
+	}
+	o.oracle.SetZeroCoin(coin)
+}
+
+func (o *MultiOracle) GetZeroCoin() int {
+	return o.oracle.GetZeroCoin()
+}
+
+// Reveal the counterfeit coins and their relative weights.
+func (o *MultiOracle) Counterfeits() []Counterfeit {
+	return sortCounterfeits(o.counterfeits)
+}
+
+// Weigh the coins by summing the deviations of the counterfeit coins on each pan.
+func (o *MultiOracle) Weigh(a []int, b []int) Weight {
+	o.oracle.check(a, b)
+	o.oracle.attempts += 1
+
+	d := 0
+	for i, c := range o.counterfeits {
+		sign := int(c.Weight) - int(Equal)
+		for _, e := range a {
+			if e == c.Coin {
+				d += sign * o.deviations[i]
+			}
+		}
+		for _, e := range b {
+			if e == c.Coin {
+				d -= sign * o.deviations[i]
+			}
+		}
+	}
+
+	switch {
+	case d < 0:
+		return Light
+	case d > 0:
+		return Heavy
+	default:
+		return Equal
+	}
+}
+
+// Sort the counterfeits by coin.
+func sortCounterfeits(c []Counterfeit) []Counterfeit {
+	r := make([]Counterfeit, len(c))
+	copy(r, c)
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].Coin < r[j].Coin || (r[i].Coin == r[j].Coin && r[i].Weight < r[j].Weight)
+	})
+	return r
+}
+
+// TestMulti checks whether decide answers exactly the specified counterfeit coins, in any order.
+func TestMulti(coins int, weighings int, counterfeits []Counterfeit, zeroCoin int, p MultiCandidate) error {
+	return testMulti(NewMultiOracle(coins, weighings, counterfeits, zeroCoin), p)
+}
+
+// testMulti checks whether decide answers exactly the counterfeit coins known to the oracle.
+func testMulti(oracle *MultiOracle, p MultiCandidate) error {
+	func() {
+		defer func() {
+			if err := recover(); err != nil {
+				oracle.oracle.err = err.(error)
+			}
+		}()
+		expected := oracle.Counterfeits()
+		actual := sortCounterfeits(p(oracle))
+		if len(actual) != len(expected) {
+			panic(fmt.Errorf("decide chose %v", actual))
+		}
+		for i, _ := range actual {
+			if actual[i] != expected[i] {
+				panic(fmt.Errorf("decide chose %v", actual))
+			}
+		}
+	}()
+	return oracle.oracle.err
+}
+
+// TestAllMulti tests the candidate against every combination of k counterfeit coins amongst
+// the specified number of coins and every combination of their relative weights.
+//
+// If same is true, the counterfeit coins share the same deviation: they are either all light
+// or all heavy by the same amount. Otherwise each counterfeit coin is independently light or
+// heavy and the coins are tested both with the same amount, so that a light and a heavy
+// counterfeit coin on the same pan cancel each other out, and with the distinct amounts
+// 1, 2, 4, ... 2^(k-1), so that no combination of counterfeit coins on the scale cancels
+// out, in every assignment of the amounts to the coins.
+func TestAllMulti(coins int, weighings int, k int, same bool, p MultiCandidate) []error {
+	combinations := 1
+	for i := 0; i < k; i++ {
+		combinations = combinations * (coins - i) / (i + 1)
+	}
+	if same {
+		combinations *= 2
+	} else {
+		combinations <<= uint(k)
+	}
+	if k < 1 || k > coins || combinations > pow3(weighings) {
+		return []error{fmt.Errorf("unsolvable: %d counterfeits of %d coins cannot be decided in %d weighings\n", k, coins, weighings)}
+	}
+
+	amounts := [][]int{make([]int, k)}
+	for i, _ := range amounts[0] {
+		amounts[0][i] = 1
+	}
+	if !same && k > 1 {
+		powers := make([]int, k)
+		for i, _ := range powers {
+			powers[i] = 1 << uint(i)
+		}
+		amounts = append(amounts, Permute(powers)...)
+	}
+
+	errors := []error{}
+	counterfeits := make([]Counterfeit, k)
+
+	var weights func(i int)
+	weights = func(i int) {
+		if i == k {
+			for _, deviations := range amounts {
+				o := NewMultiOracleWithDeviations(coins, weighings, counterfeits, deviations, 0)
+				if err := testMulti(o, p); err != nil {
+					if same {
+						errors = append(errors, fmt.Errorf("fail: for %v: %v\n", counterfeits, err))
+					} else {
+						errors = append(errors, fmt.Errorf("fail: for %v with deviations %v: %v\n", counterfeits, deviations, err))
+					}
+				}
+			}
+			return
+		}
+		for _, w := range []Weight{Light, Heavy} {
+			if same && i > 0 && w != counterfeits[0].Weight {
+				continue
+			}
+			counterfeits[i].Weight = w
+			weights(i + 1)
+		}
+	}
+
+	var choose func(i int, next int)
+	choose = func(i int, next int) {
+		if i == k {
+			weights(0)
+			return
+		}
+		for c := next; c < coins; c++ {
+			counterfeits[i].Coin = c
+			choose(i+1, c+1)
+		}
+	}
+	choose(0, 0)
+
+	return errors
+}
@@ -0,0 +1,59 @@
+package lib
+
+import (
+	"testing"
+)
+
+// decides which 2 of 3 coins are counterfeit, given that both have the same weight.
+func decide2of3(scale Scale) []Counterfeit {
+	scale.SetZeroCoin(0)
+	r := scale.Weigh([]int{0}, []int{1})
+	if r == Equal {
+		w := scale.Weigh([]int{0}, []int{2})
+		return []Counterfeit{{0, w}, {1, w}}
+	}
+	if scale.Weigh([]int{0}, []int{2}) == Equal {
+		return []Counterfeit{{0, r}, {2, r}}
+	}
+	return []Counterfeit{{2, r.Invert()}, {1, r.Invert()}}
+}
+
+func TestAllMultiSameWeight(t *testing.T) {
+	if errors := TestAllMulti(3, 2, 2, true, decide2of3); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+}
+
+func TestAllMultiDifferentWeights(t *testing.T) {
+	// decide2of3 assumes that the counterfeits have the same weight, so it fails for 6
+	// combinations of a light and a heavy counterfeit with the same amount and, with
+	// different amounts, for 20 in which the difference between the amounts decides a
+	// weighing.
+	if errors := TestAllMulti(3, 3, 2, false, decide2of3); len(errors) != 26 {
+		t.Fatalf("expected 26 failures: was: %d: %v", len(errors), errors)
+	}
+}
+
+func TestMultiOracleDeviations(t *testing.T) {
+	counterfeits := []Counterfeit{{1, Heavy}, {0, Light}}
+	if w := NewMultiOracle(4, 1, counterfeits, 0).Weigh([]int{0, 1}, []int{2, 3}); w != Equal {
+		t.Fatalf("assertion failed: was: %v expected: %v", w, Equal)
+	}
+	o := NewMultiOracleWithDeviations(4, 1, counterfeits, []int{2, 1}, 0)
+	if w := o.Weigh([]int{0, 1}, []int{2, 3}); w != Heavy {
+		t.Fatalf("assertion failed: was: %v expected: %v", w, Heavy)
+	}
+	if c := o.Counterfeits(); len(c) != 2 || c[0] != (Counterfeit{0, Light}) || c[1] != (Counterfeit{1, Heavy}) {
+		t.Fatalf("assertion failed: was: %v expected: %v", c, sortCounterfeits(counterfeits))
+	}
+}
+
+func TestMultiOracleSingleCounterfeit(t *testing.T) {
+	errors := TestAllMulti(3, 2, 1, false, func(scale Scale) []Counterfeit {
+		coin, w := decide3(scale)
+		return []Counterfeit{{coin, w}}
+	})
+	if len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+}
@@ -0,0 +1,122 @@
+package lib
+
+import (
+	"fmt"
+)
+
+// A NumericScale measures two collections of coins and answers the signed
+// difference between the weight of the left collection and the weight of the
+// right collection. The weight of a genuine coin is known, so the difference only
+// reflects the deviation of the counterfeit coin and the collections need not
+// contain the same number of coins.
+type NumericScale interface {
+	Rebaseable
+	Measure(a []int, b []int) int
+}
+
+// A NumericCandidate decides the counterfeit coin and its relative weight with a numeric scale.
+type NumericCandidate func(NumericScale) (int, Weight)
+
+// A NumericOracle implements the NumericScale interface and happens to know which
+// coin is the different coin and by how much its weight deviates from a genuine coin.
+type NumericOracle struct {
+	Oracle
+	deviation int
+}
+
+// Answer the largest number of coins for which the counterfeit coin and its relative
+// weight can be found in the specified number of measurements, (3^K-1)/2.
+//
+// Since each coin appears at most once in each measurement, the magnitude of a
+// measurement only ever repeats the magnitude of the deviation, so only its sign
+// carries information. The gain over a balance comes from not having to balance the
+// number of coins on each pan, which is equivalent to having a reference coin.
+func MaxNumericCoins(weighings int) int {
+	return (pow3(weighings) - 1) / 2
+}
+
+// Create a numeric oracle for a problem of the specified number of coins that allows
+// at most maxWeighings uses of the scale. The deviation is the non-zero difference
+// between the weight of the counterfeit coin and the weight of a genuine coin.
+func NewNumericOracle(coins int, maxWeighings int, coin int, deviation int, zeroCoin int) *NumericOracle {
+	w := Heavy
+	if deviation < 0 {
+		w = Light
+	}
+	o := &NumericOracle{
+		Oracle:    *NewOracleN(coins, maxWeighings, coin, w, zeroCoin),
+		deviation: deviation,
+	}
+	o.unbalanced = true
+	return o
+}
+
+// Measure the difference between the weights of the two collections of coins.
+func (o *NumericOracle) Measure(a []int, b []int) int {
+	r := o.Oracle.Weigh(a, b)
+	return (int(r) - int(Equal)) * abs(o.deviation)
+}
+
+// Signs adapts a numeric scale to the Scale interface by answering only the sign
+// of each measurement.
+func Signs(scale NumericScale) Scale {
+	return &signScale{scale: scale}
+}
+
+type signScale struct {
+	scale NumericScale
+}
+
+func (s *signScale) SetZeroCoin(coin int) {
+	s.scale.SetZeroCoin(coin)
+}
+
+func (s *signScale) GetZeroCoin() int {
+	return s.scale.GetZeroCoin()
+}
+
+func (s *signScale) Weigh(a []int, b []int) Weight {
+	d := s.scale.Measure(a, b)
+	switch {
+	case d < 0:
+		return Light
+	case d > 0:
+		return Heavy
+	default:
+		return Equal
+	}
+}
+
+// Decide the counterfeit coin and its relative weight using a numeric scale.
+func (s *Solution) DecideNumeric(scale NumericScale) (int, Weight) {
+	return s.Decide(Signs(scale))
+}
+
+// TestNumeric checks whether decide answers the right coin and relative weight for a given
+// coin and deviation of a problem with the specified number of coins and measurements.
+func TestNumeric(coins int, weighings int, i int, deviation int, zeroCoin int, p NumericCandidate) error {
+	o := NewNumericOracle(coins, weighings, i, deviation, zeroCoin)
+	return test(&o.Oracle, Signs(o), func(Scale) (int, Weight) {
+		return p(o)
+	}, false)
+}
+
+// TestAllNumeric tests the candidate against all possibilities of a problem with the
+// specified number of coins and measurements and each of the specified deviations.
+func TestAllNumeric(coins int, weighings int, deviations []int, p NumericCandidate) []error {
+	if coins < 1 || coins > MaxNumericCoins(weighings) {
+		return []error{fmt.Errorf("unsolvable: %d coins cannot be decided in %d measurements\n", coins, weighings)}
+	}
+	errors := []error{}
+	for _, d := range deviations {
+		if d == 0 {
+			return []error{fmt.Errorf("invalid deviation: %d", d)}
+		}
+		for i := 0; i < coins; i++ {
+			if err := TestNumeric(coins, weighings, i, d, 0, p); err != nil {
+				errors = append(errors, fmt.Errorf("fail: for (%d, %d): %v\n", i, d, err))
+			}
+		}
+	}
+	return errors
+}
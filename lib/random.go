@@ -0,0 +1,74 @@
+package lib
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// The number of permutations of the 12 coins, 12!.
+const permutations = MAX_N / 176
+
+// Answer the name of the canonical structure encoded by S, one of ppp, qpp, prs, prt or qrs.
+func StructureName(S uint) string {
+	_, types := DecodeStructure(S)
+	name := ""
+	for _, t := range types {
+		name += t.String()
+	}
+	return name
+}
+
+// Answer the values of S that encode the named canonical structure.
+func StructureClass(name string) ([]uint, error) {
+	class := []uint{}
+	for S := uint(0); S < 22; S++ {
+		if StructureName(S) == name {
+			class = append(class, S)
+		}
+	}
+	if len(class) == 0 {
+		return nil, fmt.Errorf("unknown structure: %s", name)
+	}
+	return class, nil
+}
+
+// Draw a valid solution of the 12 coins problem uniformly at random from the 12!*176
+// solution numbers. The coins on each pan are also shuffled uniformly.
+func RandomSolution(r *rand.Rand) (*Solution, error) {
+	return RandomRestrictedSolution(r, nil, nil)
+}
+
+// Draw a valid solution of the 12 coins problem uniformly at random from the solution
+// numbers whose S is one of structures and whose F is one of flips. An empty slice
+// places no restriction on the corresponding value.
+func RandomRestrictedSolution(r *rand.Rand, structures []uint, flips []uint) (*Solution, error) {
+	S := uint(r.Intn(22))
+	if len(structures) > 0 {
+		S = structures[r.Intn(len(structures))]
+	}
+	F := uint(r.Intn(8))
+	if len(flips) > 0 {
+		F = flips[r.Intn(len(flips))]
+	}
+	if S >= 22 || F >= 8 {
+		return nil, fmt.Errorf("S must be less than 22 and F less than 8: S: %d, F: %d", S, F)
+	}
+	p := uint(r.Int63n(int64(permutations)))
+
+	s, err := DecodeSolution(p*176 + F*22 + S)
+	if err != nil {
+		return s, err
+	}
+	for i, w := range s.Weighings {
+		pans := w.Pans()
+		for j, pan := range pans {
+			coins := pan.AsCoins(0)
+			r.Shuffle(len(coins), func(a, b int) {
+				coins[a], coins[b] = coins[b], coins[a]
+			})
+			pans[j] = NewOrderedCoinSet(coins, 0)
+		}
+		s.Weighings[i] = NewWeighing(pans[0], pans[1])
+	}
+	return s, nil
+}
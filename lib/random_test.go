@@ -0,0 +1,53 @@
+package lib
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRandomSolution(t *testing.T) {
+	a := rand.New(rand.NewSource(1))
+	b := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		x, err := RandomSolution(a)
+		if err != nil {
+			t.Fatalf("random: %v", err)
+		}
+		y, _ := RandomSolution(b)
+		if x.String() != y.String() {
+			t.Fatalf("assertion failed: was: %v expected: %v", x, y)
+		}
+		r := x.Reset()
+		if !r.IsValid() {
+			t.Fatalf("invalid solution: %v", r)
+		}
+		if n, err := r.N(); err != nil || n != *x.encoding.N {
+			t.Fatalf("assertion failed: was: %d, %v expected: %d", n, err, *x.encoding.N)
+		}
+	}
+}
+
+func TestRandomRestrictedSolution(t *testing.T) {
+	prt, err := StructureClass("prt")
+	if err != nil {
+		t.Fatalf("structure class: %v", err)
+	}
+	if len(prt) != 6 || prt[0] != 10 {
+		t.Fatalf("assertion failed: was: %v expected: [10 ... 15]", prt)
+	}
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 20; i++ {
+		s, err := RandomRestrictedSolution(r, prt, []uint{5})
+		if err != nil {
+			t.Fatalf("random: %v", err)
+		}
+		if a, err := s.Reset().AnalyseStructure(); err != nil {
+			t.Fatalf("structure: %v", err)
+		} else if StructureName(*a.encoding.S) != "prt" || *a.encoding.F != 5 {
+			t.Fatalf("assertion failed: was: %d, %d expected: prt, 5", *a.encoding.S, *a.encoding.F)
+		}
+	}
+	if _, err := StructureClass("pqr"); err == nil {
+		t.Fatalf("expected an error for an unknown structure")
+	}
+}
@@ -0,0 +1,240 @@
+package lib
+
+import (
+	"fmt"
+)
+
+// An Edit changes the pan on which a coin is placed in one weighing. If With is
+// set, the coin instead exchanges places with another coin of the same weighing or,
+// if Across is also set, with a coin of the weighing Across that is not weighed in
+// Weighing, so that each coin takes the place of the other in the other weighing.
+//
+// Weighings are numbered from 0 and pans are named "left", "right" or "none", if the
+// coin is not weighed.
+type Edit struct {
+	Weighing int    `json:"weighing"`
+	Coin     int    `json:"coin"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	With     *int   `json:"with,omitempty"`
+	Across   *int   `json:"across,omitempty"`
+}
+
+// A Repair is a valid solution together with the edits that derive it from an
+// invalid solution.
+type Repair struct {
+	Solution *Solution `json:"solution"`
+	Edits    []Edit    `json:"edits"`
+}
+
+// an edit of the placements of coins, encoded as signature digits.
+type edit struct {
+	cells  [][2]int // the coin and weighing of each placement that is changed
+	to     []Weight // the new digit of each placement
+	report Edit
+}
+
+func panName(d Weight) string {
+	switch d {
+	case Heavy:
+		return "left"
+	case Light:
+		return "right"
+	default:
+		return "none"
+	}
+}
+
+// Repair searches for the valid solutions that are nearest to the receiver and answers
+// at most k of them, nearest first. The distance between two solutions is the number
+// of edits, either moves of a coin to another pan, or off the scale, in one weighing, swaps
+// of two coins that are placed differently in one weighing or swaps of two coins between
+// two weighings, needed to derive one from the other. No solution more than maxEdits edits
+// away is considered.
+//
+// The search considers sets of edits that each change different placements, in order of
+// increasing size, so a solution is always found with the fewest edits. For the basic
+// problem validity is checked with the signatures of the coins, otherwise with Reverse.
+func (s *Solution) Repair(k int, maxEdits int) ([]Repair, error) {
+	clone := s.Clone()
+	clone.reset()
+
+	if err := clone.checkWeighings(); err != nil {
+		return nil, err
+	}
+
+	z := clone.GetZeroCoin()
+	n := clone.CoinCount()
+	w := len(clone.Weighings)
+	if _, reference := clone.GenuineCoin(); reference {
+		n++
+	}
+	simple := !clone.Numeric() && !clone.OddCoinOnly() && clone.Lies() == 0 && n == clone.CoinCount()
+
+	// digits[i][j] is the pan of coin z+i in weighing j
+	digits := make([][]Weight, n)
+	for i, _ := range digits {
+		digits[i] = make([]Weight, w)
+		for j, _ := range digits[i] {
+			digits[i][j] = Equal
+		}
+	}
+	for j, wt := range clone.Weighings {
+		for _, c := range wt.Left().AsCoins(z) {
+			digits[c-z][j] = Heavy
+		}
+		for _, c := range wt.Right().AsCoins(z) {
+			digits[c-z][j] = Light
+		}
+	}
+
+	edits := []edit{}
+	for j := 0; j < w; j++ {
+		for i := 0; i < n; i++ {
+			for _, d := range []Weight{Light, Equal, Heavy} {
+				if d != digits[i][j] {
+					edits = append(edits, edit{
+						cells:  [][2]int{{i, j}},
+						to:     []Weight{d},
+						report: Edit{Weighing: j, Coin: z + i, From: panName(digits[i][j]), To: panName(d)},
+					})
+				}
+			}
+			for l := i + 1; l < n; l++ {
+				if digits[i][j] != digits[l][j] {
+					edits = append(edits, edit{
+						cells:  [][2]int{{i, j}, {l, j}},
+						to:     []Weight{digits[l][j], digits[i][j]},
+						report: Edit{Weighing: j, Coin: z + i, From: panName(digits[i][j]), To: panName(digits[l][j]), With: pi(z + l)},
+					})
+				}
+			}
+		}
+	}
+	// coin i leaves weighing j for the place of coin l in weighing m, which takes
+	// the place of coin i in weighing j
+	for j := 0; j < w; j++ {
+		for m := j + 1; m < w; m++ {
+			for i := 0; i < n; i++ {
+				if digits[i][j] == Equal || digits[i][m] != Equal {
+					continue
+				}
+				for l := 0; l < n; l++ {
+					if digits[l][m] == Equal || digits[l][j] != Equal {
+						continue
+					}
+					edits = append(edits, edit{
+						cells:  [][2]int{{i, j}, {i, m}, {l, m}, {l, j}},
+						to:     []Weight{Equal, digits[l][m], Equal, digits[i][j]},
+						report: Edit{Weighing: j, Coin: z + i, From: panName(digits[i][j]), To: panName(digits[l][m]), With: pi(z + l), Across: pi(m)},
+					})
+				}
+			}
+		}
+	}
+
+	sigs := make([]int, n)
+	used := make([]int, pow3(w))
+	generation := 0
+
+	valid := func() bool {
+		for i, row := range digits {
+			sigs[i] = 0
+			for _, d := range row {
+				sigs[i] = sigs[i]*3 + int(d)
+			}
+		}
+		if !simple {
+			return true
+		}
+		for j := 0; j < w; j++ {
+			balance := 0
+			for _, row := range digits {
+				balance += int(row[j]) - int(Equal)
+			}
+			if balance != 0 {
+				return false
+			}
+		}
+		generation++
+		used[half(w)] = generation
+		for _, sig := range sigs {
+			if used[sig] == generation || used[mirror(sig, w)] == generation {
+				return false
+			}
+			used[sig] = generation
+		}
+		return true
+	}
+
+	repairs := []Repair{}
+	seen := map[string]bool{}
+	chosen := []int{}
+	touched := map[[2]int]bool{}
+
+	accept := func() {
+		key := fmt.Sprint(sigs)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		candidate := newSolutionFromSignatures(sigs, w, z)
+		candidate.encoding = clone.encoding.problem()
+		if candidate.CoinCount() != clone.CoinCount() {
+			candidate.SetCoinCount(clone.CoinCount())
+		}
+		r, err := candidate.Reverse()
+		if err != nil {
+			return
+		}
+		r.Encode()
+		repair := Repair{Solution: r, Edits: []Edit{}}
+		for _, c := range chosen {
+			repair.Edits = append(repair.Edits, edits[c].report)
+		}
+		repairs = append(repairs, repair)
+	}
+
+	var search func(next int, remaining int)
+	search = func(next int, remaining int) {
+		if len(repairs) >= k {
+			return
+		}
+		if remaining == 0 {
+			if valid() {
+				accept()
+			}
+			return
+		}
+		for c := next; c < len(edits); c++ {
+			e := edits[c]
+			free := true
+			for _, cell := range e.cells {
+				free = free && !touched[cell]
+			}
+			if !free {
+				continue
+			}
+			saved := make([]Weight, len(e.cells))
+			for x, cell := range e.cells {
+				touched[cell] = true
+				saved[x] = digits[cell[0]][cell[1]]
+				digits[cell[0]][cell[1]] = e.to[x]
+			}
+			chosen = append(chosen, c)
+
+			search(c+1, remaining-1)
+
+			chosen = chosen[:len(chosen)-1]
+			for x, cell := range e.cells {
+				digits[cell[0]][cell[1]] = saved[x]
+				delete(touched, cell)
+			}
+		}
+	}
+
+	for d := 0; d <= maxEdits && len(repairs) < k; d++ {
+		search(0, d)
+	}
+	return repairs, nil
+}
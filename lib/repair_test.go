@@ -0,0 +1,64 @@
+package lib
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepairUnsplitPairs(t *testing.T) {
+	repairs, err := load(t, filepath.Join("invalid", "unsplit-pairs.json")).Repair(3, 2)
+	if err != nil {
+		t.Fatalf("repair: %v", err)
+	}
+	if len(repairs) != 3 {
+		t.Fatalf("assertion failed: was: %d expected: %d", len(repairs), 3)
+	}
+	for _, r := range repairs {
+		if len(r.Edits) != 1 {
+			t.Fatalf("assertion failed: was: %d expected: %d", len(r.Edits), 1)
+		}
+		if errors := TestAll(r.Solution.Decide); len(errors) != 0 {
+			t.Fatalf("unexpected failures: %v", errors)
+		}
+	}
+}
+
+func TestRepairValid(t *testing.T) {
+	repairs, err := load(t, "canonical.json").Repair(1, 2)
+	if err != nil {
+		t.Fatalf("repair: %v", err)
+	}
+	if len(repairs) != 1 || len(repairs[0].Edits) != 0 {
+		t.Fatalf("unexpected repairs: %v", repairs)
+	}
+}
+
+func TestRepairAcrossWeighings(t *testing.T) {
+	// 39-coins.json with coin 5 of weighing 0 and coin 11 of weighing 1 exchanged
+	s := &Solution{}
+	if err := json.Unmarshal([]byte(`{"weighings":[
+		[[3,4,10,12,13,16,17,21,23,24,28,33,37],[1,11,7,9,15,19,20,22,26,27,31,34,39]],
+		[[2,6,7,5,19,20,21,23,30,33,37,38,39],[1,3,4,9,14,15,16,17,24,27,29,32,35]],
+		[[1,3,9,10,11,12,18,23,27,29,30,31,36],[2,4,5,6,7,13,15,16,17,25,28,32,39]],
+		[[4,5,7,8,9,10,15,18,25,26,30,32,37],[6,13,14,17,19,22,24,27,29,31,33,38,39]]
+	],"zero-coin":1}`), s); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	s.DecodeJSON()
+	repairs, err := s.Repair(10, 1)
+	if err != nil {
+		t.Fatalf("repair: %v", err)
+	}
+	if len(repairs) == 0 {
+		t.Fatalf("expected a repair with 1 edit")
+	}
+	for _, r := range repairs {
+		if len(r.Edits) != 1 || r.Edits[0].Across == nil {
+			t.Fatalf("unexpected edits: %v", r.Edits)
+		}
+		if errors := TestAllN(39, 4, r.Solution.Decide); len(errors) != 0 {
+			t.Fatalf("unexpected failures: %v", errors)
+		}
+	}
+}
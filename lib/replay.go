@@ -0,0 +1,56 @@
+package lib
+
+import (
+	"fmt"
+)
+
+// A ReplayScale implements the Scale interface by answering the results of weighings
+// that were performed elsewhere, for example with a physical balance, in order.
+type ReplayScale struct {
+	results  Outcome
+	attempts int
+	zeroCoin int
+}
+
+// Create a scale that answers the specified results in order.
+func NewReplayScale(results Outcome) *ReplayScale {
+	return &ReplayScale{
+		results:  results,
+		zeroCoin: ONE_BASED,
+	}
+}
+
+func (r *ReplayScale) SetZeroCoin(coin int) {
+	r.zeroCoin = coin
+}
+
+func (r *ReplayScale) GetZeroCoin() int {
+	return r.zeroCoin
+}
+
+// Answer the next recorded result, regardless of the coins.
+func (r *ReplayScale) Weigh(a []int, b []int) Weight {
+	if r.attempts == len(r.results) {
+		panic(fmt.Errorf("too many attempts to use the scale!"))
+	}
+	r.attempts += 1
+	return r.results[r.attempts-1]
+}
+
+// DecideOutcome decides the counterfeit coin and its relative weight from the observed results
+// of the weighings of a reversed solution. An error is answered if no counterfeit coin could
+// produce the results, which means that the weighings were not performed as specified or the
+// balance is not reliable.
+func (s *Solution) DecideOutcome(results Outcome) (int, Weight, error) {
+	if s.flags&REVERSED == 0 {
+		return 0, Equal, fmt.Errorf("this solution must be reversed first")
+	}
+	if len(results) != len(s.Weighings) {
+		return 0, Equal, fmt.Errorf("expected %d results, got %d", len(s.Weighings), len(results))
+	}
+	if !s.possible(results) {
+		return 0, Equal, fmt.Errorf("physical inconsistency: no counterfeit coin can produce the outcome %v", results)
+	}
+	c, w := s.Decide(NewReplayScale(results))
+	return c, w, nil
+}
@@ -27,17 +27,30 @@ func (s *Solution) Reverse() (*Solution, error) {
 	clone.reset()
 	clone.markInvalid()
 
-	clone.Coins = make([]int, 27, 27)
-	clone.Weights = make([]Weight, 27, 27)
+	if err := clone.checkWeighings(); err != nil {
+		s.flags = INVALID
+		return s, err
+	}
+
+	k := len(clone.Weighings)
+	h := half(k)
+	n := clone.CoinCount()
+	z := clone.GetZeroCoin()
+
+	clone.Coins = make([]int, 2*h+1, 2*h+1)
+	clone.Weights = make([]Weight, 2*h+1, 2*h+1)
 	if clone.flags&RECURSE == 0 {
 		clone.encoding.Flip = nil
 	}
 
 	for i, _ := range clone.Coins {
-		clone.Coins[i] = clone.GetZeroCoin()
+		clone.Coins[i] = z
 		clone.Weights[i] = Equal
 	}
 
+	used := make([]bool, len(clone.Coins))
+	clone.Undetermined = []int{}
+
 	failures := make(map[int]bool)
 
 	fail := func(coin int, weight Weight) {
@@ -51,19 +64,32 @@ func (s *Solution) Reverse() (*Solution, error) {
 		}
 	}
 
+	// each placement of lies by the scale must lead to the same coin and weight
+	placements := LiePlacements(k, clone.Lies())
+
 	for _, w := range []Weight{Light, Heavy} {
-		for _, i := range []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12} {
-			o := NewOracle(i, w, 1)
-			ri, _, rx := clone.decide(o)
-			if ri != i {
-				if clone.Weights[rx] != Equal {
+		for i := z; i < z+n; i++ {
+			for _, lies := range placements {
+				_, _, rx := clone.decide(clone.oracle(i, w, lies))
+				if used[rx] && clone.Coins[rx] == i && clone.Weights[rx] == w {
+					// another placement of lies leads to the same outcome
+					continue
+				}
+				if used[rx] {
+					if clone.Coins[rx] == i && clone.OddCoinOnly() {
+						// the outcome identifies the coin, but not its weight
+						clone.Weights[rx] = Equal
+						clone.Undetermined = append(clone.Undetermined, i)
+						continue
+					}
 					fail(clone.Coins[rx], clone.Weights[rx])
 					fail(i, w)
 					continue
 				}
+				used[rx] = true
 				clone.Coins[rx] = i
+				clone.Weights[rx] = w
 			}
-			clone.Weights[rx] = w
 		}
 	}
 
@@ -74,10 +100,12 @@ func (s *Solution) Reverse() (*Solution, error) {
 
 	// exploit symmetry where it exists
 
-	if clone.Weights[0] == Equal {
-		clone.Coins = clone.Coins[1:13]
-		clone.Weights = clone.Weights[1:13]
-	} else {
+	if used[h] {
+		// the coin that is never weighed breaks the symmetry
+	} else if !used[0] {
+		clone.Coins = clone.Coins[1:h]
+		clone.Weights = clone.Weights[1:h]
+	} else if f := clone.singleFlip(); f >= 0 {
 
 		//
 		// A curious truth is that within the first 13 positions, of the
@@ -97,16 +125,13 @@ func (s *Solution) Reverse() (*Solution, error) {
 		// the empty slot to happen at something other than 0 and flipping
 		// the contribution of that weighing to the sum.
 		//
+		// With more than 3 weighings, the empty slot may need more than
+		// one weighing to be flipped, in which case the full table is
+		// retained. Flip() can be used to physically flip the weighings
+		// in that case.
+		//
 
-		if clone.Weights[8] == Equal {
-			clone.encoding.Flip = pi(0)
-		} else if clone.Weights[6] == Equal {
-			clone.encoding.Flip = pi(1)
-		} else if clone.Weights[2] == Equal {
-			clone.encoding.Flip = pi(2)
-		} else {
-			panic(fmt.Errorf("unexpected case: %v!", clone.Weights))
-		}
+		clone.encoding.Flip = pi(f)
 		if clone.flags&RECURSE != 0 {
 			panic(fmt.Errorf("infinite recursion detected: %v", clone.Weights))
 		}
@@ -119,3 +144,114 @@ func (s *Solution) Reverse() (*Solution, error) {
 	clone.flags |= REVERSED
 	return clone, nil
 }
+
+// Answer a scale for the problem of the receiver that knows the counterfeit coin, its
+// relative weight and which uses of the scale give the wrong answer.
+func (s *Solution) oracle(coin int, w Weight, lies []Lie) Scale {
+	k := len(s.Weighings)
+	n := s.CoinCount()
+	z := s.GetZeroCoin()
+	_, reference := s.GenuineCoin()
+	if s.Numeric() {
+		o := NewNumericOracle(n, k, coin, int(w)-int(Equal), z)
+		o.reference = reference
+		return Signs(o)
+	} else if reference {
+		return NewReferenceOracle(n, k, coin, w, z)
+	} else if len(lies) > 0 {
+		return NewLyingOracle(n, k, coin, w, z, lies)
+	} else {
+		return NewOracleN(n, k, coin, w, z)
+	}
+}
+
+// Check that the weighings only use coins of the problem, use each coin at
+// most once per weighing and place the same number of coins on each pan.
+func (s *Solution) checkWeighings() error {
+	n := s.CoinCount()
+	z := s.GetZeroCoin()
+	g, reference := s.GenuineCoin()
+	if len(s.Weighings) == 0 {
+		return fmt.Errorf("not a valid solution: no weighings")
+	}
+	if n > 63 {
+		return fmt.Errorf("not a valid solution: too many coins: %d", n)
+	}
+	if reference && g != z+n {
+		return fmt.Errorf("not a valid solution: the genuine coin must be numbered %d", z+n)
+	}
+	if s.Lies() < 0 {
+		return fmt.Errorf("not a valid solution: the number of lies must not be negative: %d", s.Lies())
+	}
+	if s.Lies() > 0 && (reference || s.OddCoinOnly() || s.Numeric()) {
+		return fmt.Errorf("not a valid solution: lies cannot be combined with a genuine coin, odd-coin-only or a numeric scale")
+	}
+	for i, w := range s.Weighings {
+		if w.Left().Intersection(w.Right()).Size() != 0 {
+			return fmt.Errorf("not a valid solution: weighing %d uses a coin twice", i)
+		}
+		if w.Left().Size() != w.Right().Size() && !s.Numeric() {
+			return fmt.Errorf("not a valid solution: weighing %d is unbalanced", i)
+		}
+		for _, c := range w.Both().AsCoins(z) {
+			if c == g && reference {
+				continue
+			}
+			if c >= z+n {
+				return fmt.Errorf("not a valid solution: weighing %d uses invalid coin: %d", i, c)
+			}
+		}
+	}
+	return nil
+}
+
+// Answer the weighing which needs to be flipped so that the LLL outcome
+// of a full outcome table is unused or -1 if no single weighing can be flipped.
+func (s *Solution) singleFlip() int {
+	k := len(s.Weighings)
+	h := half(k)
+	for f := 0; f < k; f++ {
+		d := 2 * pow3(k-1-f)
+		for _, j := range []int{2*h - d, d} {
+			if j < h && s.Weights[j] == Equal {
+				return f
+			}
+		}
+	}
+	return -1
+}
+
+// Answer the weighings which need to be flipped so that the LLL outcome
+// of a full outcome table is unused. Answers an empty slice if the table
+// is not full, already has LLL unused or has no unused slot that can be
+// moved to LLL by flipping weighings.
+func (s *Solution) multipleFlips() []int {
+	k := len(s.Weighings)
+	h := half(k)
+	if len(s.Weights) != 2*h+1 || s.Weights[0] == Equal {
+		return []int{}
+	}
+	for j := 1; j < h; j++ {
+		if s.Weights[j] != Equal {
+			continue
+		}
+		lights := []int{}
+		heavies := []int{}
+		for f, d := k-1, j; f >= 0; f, d = f-1, d/3 {
+			switch Weight(d % 3) {
+			case Light:
+				lights = append(lights, f)
+			case Heavy:
+				heavies = append(heavies, f)
+			}
+		}
+		if len(lights)+len(heavies) != k {
+			continue
+		} else if len(heavies) <= len(lights) {
+			return heavies
+		} else {
+			return lights
+		}
+	}
+	return []int{}
+}
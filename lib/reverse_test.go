@@ -0,0 +1,233 @@
+package lib
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// load a solution from the examples directory.
+func load(t *testing.T, name string) *Solution {
+	f, err := os.Open(filepath.Join("..", "examples", name))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	s := &Solution{}
+	if err := json.NewDecoder(f).Decode(s); err != nil {
+		t.Fatalf("decode: %s: %v", name, err)
+	}
+	s.DecodeJSON()
+	return s
+}
+
+func TestReverseValidExamples(t *testing.T) {
+	for _, name := range []string{"canonical.json", "frank.json", "iwriteiam.json", "needsflip.json", "simple.json"} {
+		r, err := load(t, name).Reverse()
+		if err != nil {
+			t.Fatalf("reverse: %s: %v", name, err)
+		}
+		if errors := TestAll(r.Decide); len(errors) != 0 {
+			t.Fatalf("unexpected failures: %s: %v", name, errors)
+		}
+	}
+}
+
+func TestReverseInvalidExamples(t *testing.T) {
+	for _, name := range []string{"too-few-coins.json", "too-few-weighings.json", "unsplit-pairs.json"} {
+		if _, err := load(t, filepath.Join("invalid", name)).Reverse(); err == nil {
+			t.Fatalf("expected reverse to fail: %s", name)
+		}
+	}
+}
+
+func TestReverse3Coins(t *testing.T) {
+	s := &Solution{
+		Weighings: []Weighing{
+			NewWeighing(NewCoinSet([]int{1}, 1), NewCoinSet([]int{2}, 1)),
+			NewWeighing(NewCoinSet([]int{1}, 1), NewCoinSet([]int{3}, 1)),
+		},
+	}
+	r, err := s.Reverse()
+	if err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	if errors := TestAllN(3, 2, r.Decide); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+}
+
+func TestReverseUnbalanced(t *testing.T) {
+	s := &Solution{
+		Weighings: []Weighing{
+			NewWeighing(NewCoinSet([]int{1}, 1), NewCoinSet([]int{2, 3}, 1)),
+			NewWeighing(NewCoinSet([]int{1}, 1), NewCoinSet([]int{3}, 1)),
+		},
+	}
+	if _, err := s.Reverse(); err == nil {
+		t.Fatalf("expected reverse to fail: %v", s)
+	}
+}
+
+func TestReverse39Coins(t *testing.T) {
+	r, err := load(t, "39-coins.json").Reverse()
+	if err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	if len(r.Coins) != 39 {
+		t.Fatalf("assertion failed: was: %d expected: %d", len(r.Coins), 39)
+	}
+	if errors := TestAllN(39, 4, r.Decide); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+}
+
+func TestReverse39CoinsFullTable(t *testing.T) {
+	// the unused outcome can only be moved to LLLL by flipping 2 weighings, so the
+	// full table of outcomes is retained.
+	s := load(t, "39-coins.json")
+	for _, i := range []int{0, 1} {
+		s.Weighings[i] = NewWeighing(s.Weighings[i].Right(), s.Weighings[i].Left())
+	}
+	r, err := s.Reverse()
+	if err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	if len(r.Coins) != 81 {
+		t.Fatalf("assertion failed: was: %d expected: %d", len(r.Coins), 81)
+	}
+	if errors := TestAllN(39, 4, r.Decide); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %d: %v", len(errors), errors[0])
+	}
+}
+
+func TestFlip39Coins(t *testing.T) {
+	s := load(t, "39-coins.json")
+	for _, i := range []int{0, 1} {
+		s.Weighings[i] = NewWeighing(s.Weighings[i].Right(), s.Weighings[i].Left())
+	}
+	r, err := s.Reverse()
+	if err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	if len(r.Coins) != 81 {
+		t.Fatalf("assertion failed: was: %d expected: %d", len(r.Coins), 81)
+	}
+	if r, err = r.Flip(); err != nil {
+		t.Fatalf("flip: %v", err)
+	}
+	if len(r.Coins) != 39 {
+		t.Fatalf("assertion failed: was: %d expected: %d", len(r.Coins), 39)
+	}
+	if errors := TestAllN(39, 4, r.Decide); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+}
+
+func TestReverse13CoinsWithGenuineCoin(t *testing.T) {
+	s := load(t, "13-coins-genuine.json")
+	r, err := s.Reverse()
+	if err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	if errors := TestAllReference(13, 3, r.Decide); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+	if g, err := r.Groupings(); err != nil {
+		t.Fatalf("groupings: %v", err)
+	} else if g.Triples.Size() != 4 || g.Unique.Size() != 3 {
+		t.Fatalf("unexpected groupings: %v", g)
+	}
+	s.encoding.Genuine = nil
+	if _, err := s.Reverse(); err == nil {
+		t.Fatalf("expected reverse to fail without the genuine coin")
+	}
+}
+
+func TestStructure13CoinsWithGenuineCoin(t *testing.T) {
+	a, err := load(t, "13-coins-genuine.json").AnalyseStructure()
+	if err != nil {
+		t.Fatalf("structure: %v", err)
+	}
+	if a.encoding.Composition == nil || len(*a.encoding.Composition) != 3 {
+		t.Fatalf("expected a composition of each weighing: %v", a)
+	}
+	expected := [2]string{"(3T, 2J)", "(1T, 2J, 1U, 1G)"}
+	if c := (*a.encoding.Composition)[0]; c != expected {
+		t.Fatalf("assertion failed: was: %v expected: %v", c, expected)
+	}
+	if _, err := a.N(); err == nil {
+		t.Fatalf("expected N to be undefined with a reference coin")
+	}
+}
+
+func TestReverse13CoinsOddCoinOnly(t *testing.T) {
+	s := load(t, "13-coins-odd-only.json")
+	r, err := s.Reverse()
+	if err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	if len(r.Undetermined) != 1 || r.Undetermined[0] != 13 {
+		t.Fatalf("assertion failed: was: %v expected: %v", r.Undetermined, []int{13})
+	}
+	if errors := TestAllOddCoin(13, 3, r.Decide); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+	if errors := TestAllN(12, 3, r.Decide); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+	s.SetOddCoinOnly(false)
+	if _, err := s.Reverse(); err == nil {
+		t.Fatalf("expected reverse to fail when the weight must be found")
+	}
+}
+
+func TestReverse5CoinsWith1Lie(t *testing.T) {
+	s := load(t, "5-coins-1-lie.json")
+	r, err := s.Reverse()
+	if err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	if errors := TestAllLies(5, 5, 1, r.Decide); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+	s.Weighings = s.Weighings[0:4]
+	if _, err := s.Reverse(); err == nil {
+		t.Fatalf("expected reverse to fail with only 4 weighings")
+	}
+	s = load(t, "5-coins-1-lie.json")
+	s.SetLies(0)
+	s.Weighings = s.Weighings[0:4]
+	if _, err := s.Reverse(); err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+}
+
+func TestReverseNegativeLies(t *testing.T) {
+	s := &Solution{}
+	if err := json.Unmarshal([]byte(`{"weighings":[[[1,10,11,12],[4,5,6,7]],[[7,8,9,12],[2,6,10,11]],[[3,5,8,10],[4,9,11,12]]],"lies":-1}`), s); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	s.DecodeJSON()
+	// without the check, a negative number of lies allows any number of lies
+	if _, err := s.Reverse(); err == nil || !strings.Contains(err.Error(), "negative") {
+		t.Fatalf("expected reverse to reject a negative number of lies: %v", err)
+	}
+}
+
+func TestReverse13CoinsNumeric(t *testing.T) {
+	s := load(t, "13-coins-numeric.json")
+	r, err := s.Reverse()
+	if err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	if errors := TestAllNumeric(13, 3, []int{-2, -1, 1, 5}, r.DecideNumeric); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+	s.SetNumeric(false)
+	if _, err := s.Reverse(); err == nil {
+		t.Fatalf("expected reverse to fail with a balance")
+	}
+}
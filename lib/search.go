@@ -0,0 +1,109 @@
+package lib
+
+import (
+	"sort"
+)
+
+// Search enumerates, from scratch, every valid static solution of the problem with the
+// specified number of coins and weighings and calls emit once for each solution, up to a
+// relabeling of the coins and the order of the pans in each weighing. Every weighing
+// of an emitted solution places the same number of coins on each pan.
+//
+// Coins are placed into pans by choosing a distinct signature for each coin (see
+// signature.go). Relabeling is eliminated by choosing the signatures of successive coins
+// in increasing order, branches that can no longer balance the pans are pruned and
+// a complete placement is only emitted if it is the least of the placements obtained
+// by swapping the pans of any subset of the weighings.
+func Search(coins int, weighings int, emit func(*Solution)) {
+	k := weighings
+	m := pow3(k)
+	h := half(k)
+	sigs := make([]int, 0, coins)
+	used := make([]bool, m)
+	balance := make([]int, k)
+
+	used[h] = true // the signature of a coin that is not weighed
+
+	var search func(next int)
+	search = func(next int) {
+		remaining := coins - len(sigs)
+		for _, b := range balance {
+			if abs(b) > remaining {
+				return
+			}
+		}
+		if remaining == 0 {
+			if isLeastFlip(sigs, k) {
+				s := newSolutionFromSignatures(sigs, k, ONE_BASED)
+				if r, err := s.Reverse(); err == nil {
+					emit(r)
+				}
+			}
+			return
+		}
+		for sig := next; sig < m; sig++ {
+			if used[sig] || used[mirror(sig, k)] {
+				continue
+			}
+			used[sig] = true
+			sigs = append(sigs, sig)
+			for j, _ := range balance {
+				balance[j] += int(digit(sig, j, k)) - int(Equal)
+			}
+
+			search(sig + 1)
+
+			for j, _ := range balance {
+				balance[j] -= int(digit(sig, j, k)) - int(Equal)
+			}
+			sigs = sigs[:len(sigs)-1]
+			used[sig] = false
+		}
+	}
+	search(0)
+}
+
+// Answer the signatures that result from swapping the pans of each weighing j
+// for which bit j of flips is set, in increasing order.
+func flipSignatures(sigs []int, flips uint, k int) []int {
+	flipped := make([]int, len(sigs))
+	for i, sig := range sigs {
+		f := 0
+		for j := 0; j < k; j++ {
+			d := digit(sig, j, k)
+			if flips&(1<<uint(j)) != 0 {
+				d = d.Invert()
+			}
+			f = f*3 + int(d)
+		}
+		flipped[i] = f
+	}
+	sort.Ints(flipped)
+	return flipped
+}
+
+// Answer true if the sorted signatures are not lexicographically greater than
+// those that result from any combination of swapped pans.
+func isLeastFlip(sigs []int, k int) bool {
+	for f := uint(1); f < 1<<uint(k); f++ {
+		if compareSignatures(flipSignatures(sigs, f, k), sigs) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare two sorted slices of signatures lexicographically.
+func compareSignatures(a []int, b []int) int {
+	for i, _ := range a {
+		if i >= len(b) || a[i] > b[i] {
+			return 1
+		} else if a[i] < b[i] {
+			return -1
+		}
+	}
+	if len(a) < len(b) {
+		return -1
+	}
+	return 0
+}
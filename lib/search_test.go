@@ -0,0 +1,36 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestSearch3Coins(t *testing.T) {
+	count := 0
+	Search(3, 2, func(s *Solution) {
+		count++
+	})
+	if count != 1 {
+		t.Fatalf("assertion failed: was: %d expected: %d", count, 1)
+	}
+}
+
+func TestSearch12Coins(t *testing.T) {
+	count := 0
+	Search(12, 3, func(s *Solution) {
+		count++
+		if !s.IsValid() {
+			t.Fatalf("invalid solution: %v", s)
+		}
+		for _, w := range s.Weighings {
+			if w.Left().Size() != 4 || w.Right().Size() != 4 {
+				t.Fatalf("unbalanced weighing: %v", s)
+			}
+		}
+		if errors := TestAll(s.Decide); len(errors) != 0 {
+			t.Fatalf("unexpected failures: %v", errors)
+		}
+	})
+	if count != 38 {
+		t.Fatalf("assertion failed: was: %d expected: %d", count, 38)
+	}
+}
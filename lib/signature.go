@@ -0,0 +1,73 @@
+package lib
+
+// The signature of a coin is the outcome of the weighings of a solution if that coin
+// were the heavy coin, encoded as the unsigned outcome index 3^(K-1)*r[0]+...+r[K-1].
+// If the coin were light, the outcome would be the mirror of the signature, 3^K-1-signature.
+//
+// A solution is valid if and only if each coin has a signature that differs from
+// the signatures of the other coins, from the mirrors of those signatures and from the
+// signature of a coin that is not weighed at all, (3^K-1)/2.
+
+// Answer the signatures of each coin of the solution.
+func (s *Solution) signatures() []int {
+	z := s.GetZeroCoin()
+	n := s.CoinCount()
+	sigs := make([]int, n)
+	for _, w := range s.Weighings {
+		for i, _ := range sigs {
+			sigs[i] *= 3
+		}
+		for i, _ := range sigs {
+			sigs[i] += int(Equal)
+		}
+		for _, c := range w.Left().AsCoins(z) {
+			if c-z < n {
+				sigs[c-z] += int(Heavy - Equal)
+			}
+		}
+		for _, c := range w.Right().AsCoins(z) {
+			if c-z < n {
+				sigs[c-z] -= int(Equal - Light)
+			}
+		}
+	}
+	return sigs
+}
+
+// Answer the mirror of a signature, that is the outcome if the coin were light.
+func mirror(sig int, k int) int {
+	return pow3(k) - 1 - sig
+}
+
+// Answer the result of weighing j of the K weighings encoded by a signature.
+func digit(sig int, j int, k int) Weight {
+	return Weight((sig / pow3(k-1-j)) % 3)
+}
+
+// Create a solution of K weighings in which coin z+i has the signature sigs[i].
+func newSolutionFromSignatures(sigs []int, k int, z int) *Solution {
+	s := &Solution{
+		Weighings: make([]Weighing, k),
+	}
+	s.SetZeroCoin(z)
+	for j, _ := range s.Weighings {
+		left := []int{}
+		right := []int{}
+		for i, sig := range sigs {
+			switch digit(sig, j, k) {
+			case Heavy:
+				left = append(left, z+i)
+			case Light:
+				right = append(right, z+i)
+			}
+		}
+		s.Weighings[j] = NewWeighing(NewCoinSet(left, z), NewCoinSet(right, z))
+	}
+	for _, sig := range sigs {
+		if sig == half(k) {
+			// an unweighed coin, so the count can't be derived from the weighings
+			s.SetCoinCount(len(sigs))
+		}
+	}
+	return s
+}
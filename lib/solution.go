@@ -26,52 +26,70 @@ type Failure struct {
 	Weight Weight `json:"weight"`
 }
 
-// Describes a possibly invalid solution to the 12 coins problem.
+// Describes a possibly invalid solution to the 12 coins problem or, more generally, to the
+// problem of finding one counterfeit coin amongst N coins with K weighings.
 type Solution struct {
 	encoding
-	Weighings [3]Weighing  `json:"-"`
-	Coins     []int        `json:"coins,omitempty"`    // a mapping between abs(9*a+3*b+c-13)-1 and the coin identity
-	Weights   []Weight     `json:"weights,omitempty"`  // a mapping between sgn(9*a+3*b+c-13)-1 and the coin weight
-	Unique    CoinSet      `json:"-"`                  // the coins that appear in one weighing
-	Pairs     [3]CoinSet   `json:"-"`                  // the pairs that appear in exactly two weighings
-	Triples   CoinSet      `json:"-"`                  // the coins that appear in all 3 weighings
-	Failures  []Failure    `json:"failures,omitempty"` // a list of tests for which the solution is ambiguous
-	Structure [3]Structure `json:"-"`                  // the structure of the permutation
-	flags     flag         // indicates that invariants are true
-	order     [3]int       // permutation that maps from canonical order to this order
-	flips     Flips        // permutation that flips from canonical order to this order
-}
-
-// Decide the relative weight of a coin by generating a linear combination of the three weighings and using
+	Weighings    []Weighing   `json:"-"`
+	Coins        []int        `json:"coins,omitempty"`        // a mapping between 12-abs(9*a+3*b+c-13) and the coin identity
+	Weights      []Weight     `json:"weights,omitempty"`      // a mapping between 12-abs(9*a+3*b+c-13) and the coin weight, inverted if 9*a+3*b+c > 13
+	Unique       CoinSet      `json:"-"`                      // the coins that appear in one weighing
+	Pairs        [3]CoinSet   `json:"-"`                      // the pairs that appear in exactly two weighings
+	Triples      CoinSet      `json:"-"`                      // the coins that appear in all 3 weighings
+	Failures     []Failure    `json:"failures,omitempty"`     // a list of tests for which the solution is ambiguous
+	Undetermined []int        `json:"undetermined,omitempty"` // the coins whose relative weight can't be determined
+	Structure    [3]Structure `json:"-"`                      // the structure of the permutation
+	flags        flag         // indicates that invariants are true
+	order        [3]int       // permutation that maps from canonical order to this order
+	flips        Flips        // permutation that flips from canonical order to this order
+}
+
+// Decide the relative weight of a coin by generating a linear combination of the weighings and using
 // this to index the array.
+//
+// With 3 weighings, the linear combination is 9*a+3*b+c-13. In general, it is the
+// sum of 3^(K-1-j)*r[j] for each weighing j less (3^K-1)/2.
 func (s *Solution) decide(scale Scale) (int, Weight, int) {
+	return s.lookup(s.weigh(scale))
+}
+
+// Weigh each weighing of the receiver with the scale and answer the results.
+func (s *Solution) weigh(scale Scale) []Weight {
 	z := s.GetZeroCoin()
 	scale.SetZeroCoin(z)
 
-	results := [3]Weight{}
+	results := make([]Weight, len(s.Weighings))
+
+	for j, w := range s.Weighings {
+		results[j] = scale.Weigh(w.Left().AsCoins(z), w.Right().AsCoins(z))
+	}
+
+	return results
+}
 
-	results[0] = scale.Weigh(s.Weighings[0].Left().AsCoins(z), s.Weighings[0].Right().AsCoins(z))
-	results[1] = scale.Weigh(s.Weighings[1].Left().AsCoins(z), s.Weighings[1].Right().AsCoins(z))
-	results[2] = scale.Weigh(s.Weighings[2].Left().AsCoins(z), s.Weighings[2].Right().AsCoins(z))
+// Look up the coin and relative weight implied by the results of the weighings. Also
+// answers the index of the Coins and Weights slices that was used.
+func (s *Solution) lookup(observed []Weight) (int, Weight, int) {
+	results := make([]Weight, len(observed))
+	copy(results, observed)
 
 	if s.encoding.Flip != nil {
 		results[*s.encoding.Flip] = Heavy - results[*s.encoding.Flip]
 	}
 
-	a := results[0]
-	b := results[1]
-	c := results[2]
-
-	i := int(a*9 + b*3 + c - 13) // must be between 0 and 26, inclusive.
+	h := half(len(results))
+	i := index(results) // must be between -h and h, inclusive.
 	o := abs(i)
-	if len(s.Coins) == 12 {
-		if o < 1 || o > 12 {
+	if len(s.Coins) == h-1 {
+		if o < 1 || o > h-1 {
 			// this can only happen if flip hasn't be set correctly.
-			panic(fmt.Errorf("index out of bounds: %d, %v", o, []Weight{a, b, c}))
+			panic(fmt.Errorf("index out of bounds: %d, %v", o, results))
 		}
-		o = o - 1
+		o = h - o - 1
 	} else {
-		o = i + 13
+		// the full table of outcomes needs no symmetry to be exploited
+		o = i + h
+		return s.Coins[o], s.Weights[o], o
 	}
 
 	f := s.Coins[o]
@@ -84,6 +102,31 @@ func (s *Solution) decide(scale Scale) (int, Weight, int) {
 	return f, w, o
 }
 
+// Answer true if the results of the weighings are a possible outcome of a
+// reversed solution.
+func (s *Solution) possible(results []Weight) bool {
+	r := make([]Weight, len(results))
+	copy(r, results)
+	if s.encoding.Flip != nil {
+		r[*s.encoding.Flip] = Heavy - r[*s.encoding.Flip]
+	}
+	h := half(len(r))
+	i := index(r)
+	if o := abs(i); len(s.Coins) == h-1 && (o < 1 || o > h-1) {
+		return false
+	}
+	c, w, _ := s.lookup(results)
+	if w == Equal && i == 0 {
+		// only the coin that is never weighed can have an undetermined weight
+		for _, u := range s.Undetermined {
+			if u == c {
+				return true
+			}
+		}
+	}
+	return w != Equal
+}
+
 // The internal reset is used to reset the analysis of the receiver but
 // does not undo the reversed state.
 func (s *Solution) reset() {
@@ -91,10 +134,7 @@ func (s *Solution) reset() {
 	s.Triples = nil
 	s.Pairs = [3]CoinSet{nil, nil, nil}
 	s.Structure = [3]Structure{nil, nil, nil}
-	s.encoding = encoding{
-		ZeroCoin: s.encoding.ZeroCoin,
-		Flip:     s.encoding.Flip,
-	}
+	s.encoding = s.encoding.problem()
 	s.flags = s.flags &^ (GROUPED | ANALYSED | CANONICALISED)
 }
 
@@ -141,6 +181,115 @@ func (s *Solution) GetZeroCoin() int {
 	}
 }
 
+// Answer the number of coins in the problem. Unless explicitly configured, this
+// is derived from the highest numbered coin, other than the genuine coin, that
+// appears in any weighing.
+func (s *Solution) CoinCount() int {
+	if s.encoding.CoinCount != nil {
+		return *s.encoding.CoinCount
+	}
+	z := s.GetZeroCoin()
+	g, reference := s.GenuineCoin()
+	n := 0
+	for _, w := range s.Weighings {
+		for _, c := range w.Both().AsCoins(z) {
+			if reference && c == g {
+				continue
+			}
+			if c-z+1 > n {
+				n = c - z + 1
+			}
+		}
+	}
+	return n
+}
+
+// Configure the number of coins in the problem.
+func (s *Solution) SetCoinCount(n int) {
+	s.encoding.CoinCount = pi(n)
+}
+
+// Answer the reference coin that is known to be genuine and true or false if the
+// problem has no reference coin. The reference coin is numbered one more than
+// the highest numbered coin of the problem.
+func (s *Solution) GenuineCoin() (int, bool) {
+	if s.encoding.Genuine == nil {
+		return 0, false
+	} else {
+		return *s.encoding.Genuine, true
+	}
+}
+
+// Configure the reference coin that is known to be genuine.
+func (s *Solution) SetGenuineCoin(coin int) {
+	s.encoding.Genuine = pi(coin)
+}
+
+// Answer true if the solution need only find the counterfeit coin, not its relative weight.
+func (s *Solution) OddCoinOnly() bool {
+	return s.encoding.OddOnly != nil && *s.encoding.OddOnly
+}
+
+// Configure whether the solution need only find the counterfeit coin.
+func (s *Solution) SetOddCoinOnly(oddCoinOnly bool) {
+	if oddCoinOnly {
+		s.encoding.OddOnly = pbool(true)
+	} else {
+		s.encoding.OddOnly = nil
+	}
+}
+
+// Answer the number of weighings whose results may be wrong.
+func (s *Solution) Lies() int {
+	if s.encoding.Lies == nil {
+		return 0
+	} else {
+		return *s.encoding.Lies
+	}
+}
+
+// Configure the number of weighings whose results may be wrong.
+func (s *Solution) SetLies(lies int) {
+	if lies > 0 {
+		s.encoding.Lies = pi(lies)
+	} else {
+		s.encoding.Lies = nil
+	}
+}
+
+// Answer the weighing whose results are flipped to index the Coins and Weights slices,
+// if any.
+func (s *Solution) FlippedWeighing() (int, bool) {
+	if s.encoding.Flip == nil {
+		return 0, false
+	} else {
+		return *s.encoding.Flip, true
+	}
+}
+
+// Answer true if the solution uses a numeric scale, which permits weighings with
+// a different number of coins on each pan.
+func (s *Solution) Numeric() bool {
+	return s.encoding.Numeric != nil && *s.encoding.Numeric
+}
+
+// Configure whether the solution uses a numeric scale.
+func (s *Solution) SetNumeric(numeric bool) {
+	if numeric {
+		s.encoding.Numeric = pbool(true)
+	} else {
+		s.encoding.Numeric = nil
+	}
+}
+
+// Answer true if the solution is for the 12 coins problem: 12 coins, 3 weighings,
+// no reference coin and both the coin and its relative weight must be found
+// by a balance that never lies.
+func (s *Solution) isTwelveCoins() bool {
+	_, reference := s.GenuineCoin()
+	return len(s.Weighings) == 3 && s.CoinCount() == 12 && !reference && !s.OddCoinOnly() && s.Lies() == 0 && !s.Numeric()
+}
+
 // Create a deep clone of the receiver.
 func (s *Solution) Clone() *Solution {
 	tmp := s.encoding.Flip
@@ -148,19 +297,17 @@ func (s *Solution) Clone() *Solution {
 		tmp = pi(*tmp)
 	}
 	clone := Solution{
-		encoding: encoding{
-			ZeroCoin: s.encoding.ZeroCoin,
-			Flip:     s.encoding.Flip,
-		},
-		Weighings: [3]Weighing{},
-		Coins:     make([]int, len(s.Coins)),
-		Weights:   make([]Weight, len(s.Weights)),
-		Unique:    s.Unique,
-		Triples:   s.Triples,
-		Failures:  make([]Failure, len(s.Failures)),
-		flags:     s.flags,
-		order:     s.order,
-		flips:     s.flips,
+		encoding:     s.encoding.problem(),
+		Weighings:    make([]Weighing, len(s.Weighings)),
+		Coins:        make([]int, len(s.Coins)),
+		Weights:      make([]Weight, len(s.Weights)),
+		Unique:       s.Unique,
+		Triples:      s.Triples,
+		Failures:     make([]Failure, len(s.Failures)),
+		Undetermined: make([]int, len(s.Undetermined)),
+		flags:        s.flags,
+		order:        s.order,
+		flips:        s.flips,
 	}
 
 	copy(clone.Pairs[0:], s.Pairs[0:])
@@ -168,6 +315,7 @@ func (s *Solution) Clone() *Solution {
 	copy(clone.Coins[0:], s.Coins[0:])
 	copy(clone.Weights[0:], s.Weights[0:])
 	copy(clone.Failures[0:], s.Failures[0:])
+	copy(clone.Undetermined[0:], s.Undetermined[0:])
 	copy(clone.Structure[0:], s.Structure[0:])
 
 	return &clone
@@ -202,6 +350,13 @@ func (s *Solution) Flip() (*Solution, error) {
 		r.encoding.Flip = nil
 		r.markInvalid()
 		return r.Reverse()
+	} else if flips := r.multipleFlips(); len(flips) > 0 {
+		for _, f := range flips {
+			w := r.Weighings[f]
+			r.Weighings[f] = NewWeighing(w.Right(), w.Left())
+		}
+		r.markInvalid()
+		return r.Reverse()
 	}
 	return r, nil
 }
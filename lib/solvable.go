@@ -0,0 +1,83 @@
+package lib
+
+// The state of knowledge about a set of coins, one of which is counterfeit, after
+// some weighings: the number of coins that may be light or heavy, that may only be light,
+// that may only be heavy and that are known to be genuine.
+type knowledge struct {
+	unknown int
+	light   int
+	heavy   int
+	genuine int
+}
+
+// Answer the number of hypotheses about the counterfeit coin and its weight.
+func (k knowledge) hypotheses() int {
+	return 2*k.unknown + k.light + k.heavy
+}
+
+// Solvable answers true if an adaptive strategy that uses at most the specified number of
+// weighings can always find the counterfeit coin and its relative weight, given the number of
+// coins that may be light or heavy, that may only be light, that may only be heavy and that are
+// known to be genuine.
+func Solvable(unknown int, light int, heavy int, genuine int, weighings int) bool {
+	memo := map[state]bool{}
+	return solvable(knowledge{unknown, light, heavy, genuine}, weighings, memo)
+}
+
+// The knowledge together with the number of weighings that remain.
+type state struct {
+	knowledge
+	weighings int
+}
+
+func solvable(k knowledge, weighings int, memo map[state]bool) bool {
+	h := k.hypotheses()
+	if h <= 1 {
+		return true
+	}
+	if h > pow3(weighings) {
+		return false
+	}
+
+	// only the number of genuine coins that can ever be placed on a pan matters
+	if total := k.unknown + k.light + k.heavy; k.genuine > total {
+		k.genuine = total
+	}
+	key := state{k, weighings}
+	if r, ok := memo[key]; ok {
+		return r
+	}
+
+	r := false
+	limit := pow3(weighings - 1)
+	for a1 := 0; a1 <= k.unknown && !r; a1++ {
+		for a2 := 0; a1+a2 <= k.unknown && !r; a2++ {
+			for b1 := 0; b1 <= k.light && !r; b1++ {
+				for b2 := 0; b1+b2 <= k.light && !r; b2++ {
+					for c1 := 0; c1 <= k.heavy && !r; c1++ {
+						for c2 := 0; c1+c2 <= k.heavy && !r; c2++ {
+							left := a1 + b1 + c1
+							right := a2 + b2 + c2
+							if left < right || abs(left-right) > k.genuine || left+right == 0 {
+								// by symmetry, the left pan is never the smaller pan
+								continue
+							}
+							weighed := left + right
+							equal := knowledge{k.unknown - a1 - a2, k.light - b1 - b2, k.heavy - c1 - c2, k.genuine + weighed}
+							heavier := knowledge{0, a2 + b2, a1 + c1, k.genuine + k.unknown + k.light + k.heavy - (a1 + a2 + b2 + c1)}
+							lighter := knowledge{0, a1 + b1, a2 + c2, k.genuine + k.unknown + k.light + k.heavy - (a1 + a2 + b1 + c2)}
+							if equal.hypotheses() > limit || heavier.hypotheses() > limit || lighter.hypotheses() > limit {
+								continue
+							}
+							r = solvable(equal, weighings-1, memo) &&
+								solvable(heavier, weighings-1, memo) &&
+								solvable(lighter, weighings-1, memo)
+						}
+					}
+				}
+			}
+		}
+	}
+	memo[key] = r
+	return r
+}
@@ -0,0 +1,25 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestSolvable(t *testing.T) {
+	for k := 2; k <= 3; k++ {
+		if !Solvable(MaxCoins(k), 0, 0, 0, k) {
+			t.Fatalf("expected %d coins to be solvable in %d weighings", MaxCoins(k), k)
+		}
+		if Solvable(MaxCoins(k)+1, 0, 0, 0, k) {
+			t.Fatalf("expected %d coins not to be solvable in %d weighings", MaxCoins(k)+1, k)
+		}
+		if !Solvable(MaxReferenceCoins(k), 0, 0, 1, k) {
+			t.Fatalf("expected %d coins and a genuine coin to be solvable in %d weighings", MaxReferenceCoins(k), k)
+		}
+	}
+	if !Solvable(0, 4, 5, 3, 2) {
+		t.Fatalf("expected 4 light and 5 heavy coins to be solvable in 2 weighings")
+	}
+	if Solvable(4, 0, 0, 8, 1) {
+		t.Fatalf("expected 4 unknown coins not to be solvable in 1 weighing")
+	}
+}
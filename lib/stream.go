@@ -0,0 +1,109 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// A binary stream of solution numbers starts with the magic header and is followed by one
+// fixed size record of NUMBER_BYTES big-endian bytes per number. Every number less than
+// MAX_N fits in 40 bits.
+const (
+	NUMBER_MAGIC = "12coins\x01"
+	NUMBER_BYTES = 5
+)
+
+// A NumberWriter writes solution numbers in the binary stream format.
+type NumberWriter struct {
+	w      *bufio.Writer
+	header bool
+	buf    [NUMBER_BYTES]byte
+}
+
+// A NumberReader reads solution numbers from a stream.
+type NumberReader interface {
+	// Answer the next number or io.EOF at the end of the stream. Numbers that are not
+	// less than MAX_N are reported as errors.
+	Read() (uint, error)
+}
+
+type binaryNumberReader struct {
+	r   *bufio.Reader
+	buf [NUMBER_BYTES]byte
+}
+
+type jsonNumberReader struct {
+	decoder *json.Decoder
+}
+
+// Create a writer of the binary stream format. The header is written with the first number.
+func NewNumberWriter(w io.Writer) *NumberWriter {
+	return &NumberWriter{w: bufio.NewWriter(w)}
+}
+
+// Write a number as a binary record.
+func (w *NumberWriter) Write(n uint) error {
+	if n >= 1<<(8*NUMBER_BYTES) {
+		return fmt.Errorf("number too large for a %d byte record: %d", NUMBER_BYTES, n)
+	}
+	if !w.header {
+		if _, err := w.w.WriteString(NUMBER_MAGIC); err != nil {
+			return err
+		}
+		w.header = true
+	}
+	for i := NUMBER_BYTES - 1; i >= 0; i-- {
+		w.buf[i] = byte(n)
+		n >>= 8
+	}
+	_, err := w.w.Write(w.buf[:])
+	return err
+}
+
+// Flush the buffered records to the underlying writer.
+func (w *NumberWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// Create a reader of solution numbers that detects whether the stream uses the binary
+// format, by the presence of the magic header, or contains JSON numbers.
+func NewNumberReader(r io.Reader) NumberReader {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(len(NUMBER_MAGIC)); err == nil && bytes.Equal(magic, []byte(NUMBER_MAGIC)) {
+		br.Discard(len(NUMBER_MAGIC))
+		return &binaryNumberReader{r: br}
+	}
+	return &jsonNumberReader{decoder: json.NewDecoder(br)}
+}
+
+func (r *binaryNumberReader) Read() (uint, error) {
+	if _, err := io.ReadFull(r.r, r.buf[:]); err == io.ErrUnexpectedEOF {
+		return 0, fmt.Errorf("truncated record")
+	} else if err != nil {
+		return 0, err
+	}
+	n := uint(0)
+	for _, b := range r.buf {
+		n = n<<8 | uint(b)
+	}
+	return inRange(n)
+}
+
+func (r *jsonNumberReader) Read() (uint, error) {
+	var n uint
+	if err := r.decoder.Decode(&n); err != nil {
+		return n, err
+	}
+	return inRange(n)
+}
+
+// Answer the number, or an error if it does not encode a solution.
+func inRange(n uint) (uint, error) {
+	if n >= MAX_N {
+		return n, fmt.Errorf("number out of range: %d", n)
+	}
+	return n, nil
+}
@@ -0,0 +1,51 @@
+package lib
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNumberStream(t *testing.T) {
+	numbers := []uint{0, 7680414865, MAX_N - 1}
+	b := &bytes.Buffer{}
+	w := NewNumberWriter(b)
+	for _, n := range numbers {
+		if err := w.Write(n); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if b.Len() != len(NUMBER_MAGIC)+NUMBER_BYTES*len(numbers) {
+		t.Fatalf("assertion failed: was: %d expected: %d", b.Len(), len(NUMBER_MAGIC)+NUMBER_BYTES*len(numbers))
+	}
+	for _, r := range []NumberReader{NewNumberReader(b), NewNumberReader(strings.NewReader("0\n7680414865\n84304281599\n"))} {
+		for _, expected := range numbers {
+			if n, err := r.Read(); err != nil || n != expected {
+				t.Fatalf("assertion failed: was: %d, %v expected: %d", n, err, expected)
+			}
+		}
+		if _, err := r.Read(); err != io.EOF {
+			t.Fatalf("assertion failed: was: %v expected: %v", err, io.EOF)
+		}
+	}
+}
+
+func TestNumberStreamOutOfRange(t *testing.T) {
+	b := &bytes.Buffer{}
+	w := NewNumberWriter(b)
+	if err := w.Write(MAX_N); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	for _, r := range []NumberReader{NewNumberReader(b), NewNumberReader(strings.NewReader("84304281600\n"))} {
+		if n, err := r.Read(); err == nil {
+			t.Fatalf("assertion failed: was: %d expected: an error", n)
+		}
+	}
+}
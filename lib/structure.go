@@ -49,6 +49,9 @@ type StructureType uint8
 // * every pair is split by one weighing and is joined by another
 // * at most one weighing has all 3 triples on the one side
 //
+// CheckInvariants (see invariants.go) checks these, and the claims about the 5 structures
+// below, against every solution found by Search. Run tools -invariants for a report.
+//
 // 2T means choose 2 triples
 // 1L means choose the left half of a split pair
 // 1U means choose 1 of the singletons
@@ -596,7 +599,9 @@ func DecodeStructure(s uint) ([3]int, [3]StructureType) {
 	panic(fmt.Errorf("illegal state: s: %d", s))
 }
 
-// Return a clone of the receiver in which the structure has been populated.
+// Return a clone of the receiver in which the structure has been populated. The P, Q, R, S
+// and T structures, S, F and N are only defined for the 12 coins problem. For a solution
+// with a reference coin, the composition of each pan is reported instead.
 func (s *Solution) AnalyseStructure() (*Solution, error) {
 	var r *Solution
 	var err error
@@ -611,6 +616,16 @@ func (s *Solution) AnalyseStructure() (*Solution, error) {
 		return r, err
 	}
 
+	if _, reference := r.GenuineCoin(); reference {
+		composition := r.composition()
+		r.encoding.Composition = &composition
+		return r, nil
+	}
+
+	if !r.isTwelveCoins() {
+		return s, fmt.Errorf("structure is only defined for solutions of the 12 coins problem")
+	}
+
 	if flips, err := r.deriveStructure(); err != nil {
 		s.markInvalid()
 		return s, err
@@ -697,6 +712,7 @@ func (s *Solution) Decode() (*Solution, error) {
 	s.Pairs[2] = NewOrderedCoinSet(p[7:9], 0)
 	s.Triples = NewOrderedCoinSet(p[9:12], 0)
 
+	s.Weighings = make([]Weighing, len(st))
 	for i, e := range st {
 		s.Structure[i] = NewStructure(e)
 		s.Structure[i].Decode(s, i, p)
@@ -0,0 +1,120 @@
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	svgCoin   = 32  // the horizontal space taken by each coin
+	svgRow    = 120 // the vertical space taken by each weighing
+	svgMargin = 20
+	svgGap    = 80 // the horizontal space between the pans
+)
+
+var svgColours = map[string]string{
+	"unique": "#add8e6",
+	"pair":   "#98fb98",
+	"triple": "#fa8072",
+	"coin":   "#dddddd",
+}
+
+// SVG renders each weighing of the solution as a balance with the coins on each pan as an
+// SVG document. Coins are coloured by their role in the groupings of the solution and each
+// weighing is annotated with its structure, where these are defined.
+func (s *Solution) SVG() (string, error) {
+	if err := s.checkWeighings(); err != nil {
+		return "", err
+	}
+
+	z := s.GetZeroCoin()
+	roles := map[int]string{}
+	letters := make([]string, len(s.Weighings))
+	// the groupings of a clone, since Groupings marks an invalid receiver as such
+	if g, err := s.Clone().Groupings(); err == nil {
+		for _, c := range g.Unique.AsCoins(z) {
+			roles[c] = "unique"
+		}
+		for _, p := range g.Pairs {
+			for _, c := range p.AsCoins(z) {
+				roles[c] = "pair"
+			}
+		}
+		for _, c := range g.Triples.AsCoins(z) {
+			roles[c] = "triple"
+		}
+		if a, err := g.AnalyseStructure(); err == nil {
+			for j, st := range a.Structure {
+				if st != nil {
+					letters[j] = strings.ToUpper(st.String())
+				}
+			}
+		}
+	}
+
+	widest := 1
+	for _, w := range s.Weighings {
+		for _, p := range w.Pans() {
+			if int(p.Size()) > widest {
+				widest = int(p.Size())
+			}
+		}
+	}
+	pan := widest*svgCoin + svgMargin
+	width := 2*pan + svgGap + 2*svgMargin
+	height := len(s.Weighings)*svgRow + 2*svgMargin + 30
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"12\">\n", width, height)
+	fmt.Fprintf(b, "<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+	for j, w := range s.Weighings {
+		top := svgMargin + j*svgRow
+		centre := width / 2
+		beam := top + 70
+
+		label := fmt.Sprintf("Weighing %d", j+1)
+		if letters[j] != "" {
+			label = fmt.Sprintf("%s (%s)", label, letters[j])
+		}
+		fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\" font-weight=\"bold\">%s</text>\n", svgMargin, top+12, label)
+
+		// the fulcrum and the beam
+		fmt.Fprintf(b, "<polygon points=\"%d,%d %d,%d %d,%d\" fill=\"#888888\"/>\n", centre, beam, centre-12, beam+30, centre+12, beam+30)
+		fmt.Fprintf(b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"black\" stroke-width=\"3\"/>\n", svgMargin+pan/2, beam, width-svgMargin-pan/2, beam)
+
+		for p, coins := range w.Pans() {
+			left := svgMargin
+			if p == 1 {
+				left = width - svgMargin - pan
+			}
+			fmt.Fprintf(b, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"black\"/>\n", left+pan/2, beam, left+pan/2, beam-8)
+			fmt.Fprintf(b, "<path d=\"M %d %d Q %d %d %d %d\" fill=\"none\" stroke=\"black\" stroke-width=\"2\"/>\n", left, beam-8, left+pan/2, beam+4, left+pan, beam-8)
+			list := coins.AsCoins(z)
+			offset := left + (pan-len(list)*svgCoin)/2 + svgCoin/2
+			for i, c := range list {
+				colour := svgColours["coin"]
+				if r, ok := roles[c]; ok {
+					colour = svgColours[r]
+				}
+				if g, reference := s.GenuineCoin(); reference && g == c {
+					colour = "#ffffff"
+				}
+				x := offset + i*svgCoin
+				fmt.Fprintf(b, "<circle cx=\"%d\" cy=\"%d\" r=\"%d\" fill=\"%s\" stroke=\"black\"/>\n", x, beam-24, svgCoin/2-2, colour)
+				fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\" text-anchor=\"middle\">%d</text>\n", x, beam-20, c)
+			}
+		}
+	}
+
+	// the legend
+	y := height - svgMargin
+	x := svgMargin
+	for _, role := range []string{"unique", "pair", "triple"} {
+		fmt.Fprintf(b, "<circle cx=\"%d\" cy=\"%d\" r=\"6\" fill=\"%s\" stroke=\"black\"/>\n", x+6, y-4, svgColours[role])
+		fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\">%s</text>\n", x+16, y, role)
+		x += 80
+	}
+	fmt.Fprintf(b, "</svg>\n")
+	return b.String(), nil
+}
@@ -0,0 +1,46 @@
+package lib
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+	svg, err := load(t, "canonical.json").SVG()
+	if err != nil {
+		t.Fatalf("svg: %v", err)
+	}
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("xml: %v", err)
+		}
+	}
+	for _, label := range []string{"Weighing 1 (T)", "Weighing 2 (P)", "Weighing 3 (R)"} {
+		if !strings.Contains(svg, label) {
+			t.Fatalf("expected label: %s", label)
+		}
+	}
+	if c := strings.Count(svg, svgColours["triple"]); c != 1+3*3 {
+		t.Fatalf("assertion failed: was: %d expected: %d", c, 1+3*3)
+	}
+}
+
+func TestSVGKeepsSolution(t *testing.T) {
+	s := &Solution{}
+	if err := json.Unmarshal([]byte(`{"weighings":[[[1,10,11,12],[4,5,6,7]],[[12,7,8,2],[9,10,11,6]],[[3,10,8,9],[11,12,4,5]]]}`), s); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	s.DecodeJSON()
+	if _, err := s.SVG(); err != nil {
+		t.Fatalf("svg: %v", err)
+	}
+	if s.flags != 0 || len(s.Failures) != 0 {
+		t.Fatalf("assertion failed: was: %d, %v expected: 0, []", s.flags, s.Failures)
+	}
+}
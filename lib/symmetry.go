@@ -0,0 +1,292 @@
+package lib
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// A Symmetry maps a solution to another solution of the same problem by permuting
+// the weighings, swapping the pans of some weighings and relabeling the coins.
+// Reordering the coins within a pan is always a symmetry, since a pan is a set.
+//
+// A reference coin that is known to be genuine is never relabeled.
+type Symmetry struct {
+	Order  []int  `json:"order"`  // weighing j of the image is weighing Order[j] of the original
+	Flips  []bool `json:"flips"`  // true if the pans of weighing j of the image are swapped
+	Labels []int  `json:"labels"` // coin z+i of the original is coin Labels[i] of the image
+}
+
+// Answer the signature of the coin in the image of the symmetry given the signature
+// of the coin in the original.
+func (y *Symmetry) signature(sig int, k int) int {
+	t := 0
+	for j := 0; j < k; j++ {
+		d := digit(sig, y.Order[j], k)
+		if y.Flips[j] {
+			d = d.Invert()
+		}
+		t = t*3 + int(d)
+	}
+	return t
+}
+
+// Answer the signatures of the coins of the receiver, including the signature of the
+// genuine coin, if any.
+func (s *Solution) allSignatures() []int {
+	sigs := s.signatures()
+	if g, reference := s.GenuineCoin(); reference {
+		z := s.GetZeroCoin()
+		sig := 0
+		for _, w := range s.Weighings {
+			d := Equal
+			for _, c := range w.Left().AsCoins(z) {
+				if c == g {
+					d = Heavy
+				}
+			}
+			for _, c := range w.Right().AsCoins(z) {
+				if c == g {
+					d = Light
+				}
+			}
+			sig = sig*3 + int(d)
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+// Enumerate the symmetries of K weighings that permute the weighings and swap pans,
+// but do not relabel coins.
+func weighingSymmetries(k int) []Symmetry {
+	identity := make([]int, k)
+	for j, _ := range identity {
+		identity[j] = j
+	}
+	result := []Symmetry{}
+	for _, order := range Permute(identity) {
+		for f := 0; f < 1<<uint(k); f++ {
+			flips := make([]bool, k)
+			for j, _ := range flips {
+				flips[j] = f&(1<<uint(j)) != 0
+			}
+			result = append(result, Symmetry{Order: order, Flips: flips})
+		}
+	}
+	return result
+}
+
+// Classify answers the representative of the orbit of the receiver under the group of
+// symmetries that permute the weighings, swap the pans of some weighings and relabel the
+// coins, together with the symmetry that maps the receiver to the representative. The
+// size of the orbit, that is the number of distinct solutions that are equivalent to the
+// receiver, is recorded in the "orbit" field of the representative.
+//
+// Every solution in the same orbit has the same representative: the coins of the
+// representative are labeled in increasing order of signature and the weighings are chosen
+// so that the sorted list of signatures is the least possible.
+func (s *Solution) Classify() (*Solution, Symmetry, error) {
+	clone := s.Clone()
+	clone.reset()
+
+	if err := clone.checkWeighings(); err != nil {
+		return s, Symmetry{}, err
+	}
+
+	k := len(clone.Weighings)
+	n := clone.CoinCount()
+	z := clone.GetZeroCoin()
+	sigs := clone.allSignatures()
+	if k > 8 {
+		return s, Symmetry{}, fmt.Errorf("too many weighings to classify: %d", k)
+	}
+
+	// the coins of the image, excluding the genuine coin, ordered by signature.
+	image := func(y *Symmetry) []int {
+		t := make([]int, n)
+		for i, _ := range t {
+			t[i] = y.signature(sigs[i], k)
+		}
+		sort.Ints(t)
+		return t
+	}
+
+	less := func(a []int, ga int, b []int, gb int) bool {
+		if ga != gb {
+			return ga < gb
+		}
+		return compareSignatures(a, b) < 0
+	}
+
+	var best Symmetry
+	var bestSigs []int
+	bestGenuine := 0
+	distinct := map[string]bool{}
+	for _, y := range weighingSymmetries(k) {
+		t := image(&y)
+		g := 0
+		if len(sigs) > n {
+			g = y.signature(sigs[n], k)
+		}
+		distinct[fmt.Sprint(g, t)] = true
+		if bestSigs == nil || less(t, g, bestSigs, bestGenuine) {
+			best, bestSigs, bestGenuine = y, t, g
+		}
+	}
+
+	// relabel the coins in increasing order of their signature in the image
+	order := make([]int, n)
+	for i, _ := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return best.signature(sigs[order[a]], k) < best.signature(sigs[order[b]], k)
+	})
+	best.Labels = make([]int, len(sigs))
+	for l, i := range order {
+		best.Labels[i] = z + l
+	}
+	if len(sigs) > n {
+		best.Labels[n] = z + n
+	}
+
+	// orbit size = (|G|/|stabiliser|) * n! / product of factorials of repeated signatures
+	orbit := big.NewInt(int64(len(distinct)))
+	orbit.Mul(orbit, new(big.Int).MulRange(1, int64(n)))
+	for i := 0; i < n; {
+		m := 1
+		for i+m < n && bestSigs[i+m] == bestSigs[i] {
+			m++
+		}
+		orbit.Div(orbit, new(big.Int).MulRange(1, int64(m)))
+		i += m
+	}
+
+	all := bestSigs
+	if len(sigs) > n {
+		all = append(append([]int{}, bestSigs...), bestGenuine)
+	}
+	r := newSolutionFromSignatures(all, k, z)
+	r.encoding = clone.encoding.problem()
+	r.encoding.Flip = nil
+	if r.CoinCount() != n {
+		r.SetCoinCount(n)
+	}
+	r.encoding.Orbit = orbit
+	return r, best, nil
+}
+
+// Answer the symmetry that undoes the receiver. The labels of the original solution
+// are numbered from zeroCoin.
+func (y Symmetry) inverse(zeroCoin int) Symmetry {
+	r := Symmetry{
+		Order:  make([]int, len(y.Order)),
+		Flips:  make([]bool, len(y.Flips)),
+		Labels: make([]int, len(y.Labels)),
+	}
+	for j, o := range y.Order {
+		r.Order[o] = j
+		r.Flips[o] = y.Flips[j]
+	}
+	for i, l := range y.Labels {
+		r.Labels[l-zeroCoin] = zeroCoin + i
+	}
+	return r
+}
+
+// Answer the symmetry that applies the receiver and then the other symmetry. The
+// labels of both symmetries are numbered from zeroCoin.
+func (y Symmetry) then(other Symmetry, zeroCoin int) Symmetry {
+	r := Symmetry{
+		Order:  make([]int, len(y.Order)),
+		Flips:  make([]bool, len(y.Flips)),
+		Labels: make([]int, len(y.Labels)),
+	}
+	for j, o := range other.Order {
+		r.Order[j] = y.Order[o]
+		r.Flips[j] = other.Flips[j] != y.Flips[o]
+	}
+	for i, l := range y.Labels {
+		r.Labels[i] = other.Labels[l-zeroCoin]
+	}
+	return r
+}
+
+// Apply the symmetry to the solution and answer the image, which uses the same zero coin
+// and problem as the solution.
+func (y Symmetry) Apply(s *Solution) (*Solution, error) {
+	z := s.GetZeroCoin()
+	k := len(s.Weighings)
+	if len(y.Order) != k || len(y.Flips) != k {
+		return nil, fmt.Errorf("symmetry of %d weighings can't be applied to %d weighings", len(y.Order), k)
+	}
+	relabel := func(coins []int) ([]int, error) {
+		r := make([]int, len(coins))
+		for i, c := range coins {
+			if c-z < 0 || c-z >= len(y.Labels) {
+				return nil, fmt.Errorf("symmetry does not relabel coin: %d", c)
+			}
+			r[i] = y.Labels[c-z]
+		}
+		return r, nil
+	}
+	r := &Solution{
+		encoding:  s.encoding.problem(),
+		Weighings: make([]Weighing, k),
+	}
+	r.encoding.Flip = nil
+	for j, o := range y.Order {
+		left, err := relabel(s.Weighings[o].Left().AsCoins(z))
+		if err != nil {
+			return nil, err
+		}
+		right, err := relabel(s.Weighings[o].Right().AsCoins(z))
+		if err != nil {
+			return nil, err
+		}
+		if y.Flips[j] {
+			left, right = right, left
+		}
+		r.Weighings[j] = NewWeighing(NewCoinSet(left, z), NewCoinSet(right, z))
+	}
+	if n := s.CoinCount(); r.CoinCount() != n {
+		r.SetCoinCount(n)
+	}
+	return r, nil
+}
+
+// Equivalent answers true and the symmetry that maps a to b if b can be obtained from
+// a by permuting the weighings, swapping the pans of some weighings and relabeling
+// the coins. The coins of both a and the image are numbered from the zero coin of a,
+// so that Apply(a) is b, up to the zero coin of b.
+func Equivalent(a, b *Solution) (Symmetry, bool) {
+	ra, ya, err := a.Classify()
+	if err != nil {
+		return Symmetry{}, false
+	}
+	rb, yb, err := b.Classify()
+	if err != nil {
+		return Symmetry{}, false
+	}
+	if len(ra.Weighings) != len(rb.Weighings) ||
+		ra.CoinCount() != rb.CoinCount() ||
+		ra.OddCoinOnly() != rb.OddCoinOnly() ||
+		ra.Lies() != rb.Lies() ||
+		ra.Numeric() != rb.Numeric() ||
+		compareSignatures(ra.allSignatures(), rb.allSignatures()) != 0 {
+		return Symmetry{}, false
+	}
+	_, referenceA := ra.GenuineCoin()
+	_, referenceB := rb.GenuineCoin()
+	if referenceA != referenceB {
+		return Symmetry{}, false
+	}
+
+	za := a.GetZeroCoin()
+	zb := b.GetZeroCoin()
+	for i, _ := range yb.Labels {
+		yb.Labels[i] += za - zb
+	}
+	return ya.then(yb.inverse(za), za), true
+}
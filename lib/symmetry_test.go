@@ -0,0 +1,74 @@
+package lib
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestClassify12Coins(t *testing.T) {
+	reps := map[string]bool{}
+	total := big.NewInt(0)
+	Search(12, 3, func(s *Solution) {
+		r, _, err := s.Classify()
+		if err != nil {
+			t.Fatalf("classify: %v", err)
+		}
+		if !reps[r.String()] {
+			reps[r.String()] = true
+			total.Add(total, r.encoding.Orbit)
+		}
+	})
+	if len(reps) != 7 {
+		t.Fatalf("assertion failed: was: %d expected: %d", len(reps), 7)
+	}
+	if total.String() != "145616486400" {
+		t.Fatalf("assertion failed: was: %v expected: %v", total, "145616486400")
+	}
+}
+
+func TestClassifyIsInvariant(t *testing.T) {
+	s := load(t, "canonical.json")
+	r, _, err := s.Classify()
+	if err != nil {
+		t.Fatalf("classify: %v", err)
+	}
+
+	y := Symmetry{Order: []int{2, 0, 1}, Flips: []bool{true, false, true}}
+	sigs := s.signatures()
+	relabeled := make([]int, len(sigs))
+	for i, sig := range sigs {
+		relabeled[len(sigs)-1-i] = y.signature(sig, 3)
+	}
+	o, _, err := newSolutionFromSignatures(relabeled, 3, ONE_BASED).Classify()
+	if err != nil {
+		t.Fatalf("classify: %v", err)
+	}
+	if o.String() != r.String() {
+		t.Fatalf("assertion failed: was: %v expected: %v", o, r)
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	a := load(t, "canonical.json")
+	b := load(t, "simple.json")
+	y, ok := Equivalent(a, b)
+	if !ok {
+		t.Fatalf("expected %v to be equivalent to %v", a, b)
+	}
+	r, err := y.Apply(a)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	for i, _ := range r.Weighings {
+		for _, p := range []int{0, 1} {
+			was := r.Weighings[i].Pan(p)
+			expected := b.Weighings[i].Pan(p)
+			if was.Size() != expected.Size() || was.Intersection(expected).Size() != expected.Size() {
+				t.Fatalf("assertion failed: was: %v expected: %v", was, expected)
+			}
+		}
+	}
+	if _, ok := Equivalent(a, load(t, "frank.json")); ok {
+		t.Fatalf("expected canonical.json not to be equivalent to frank.json")
+	}
+}
@@ -0,0 +1,128 @@
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A Verdict is the coin and relative weight implied by an outcome of a solution.
+type Verdict struct {
+	Outcome Outcome `json:"outcome"`
+	Coin    int     `json:"coin"`
+	Weight  Weight  `json:"weight"`
+}
+
+// An OutcomeRow is a row of the full outcome table of a solution. The coin and
+// weight are only set if the outcome is possible.
+type OutcomeRow struct {
+	Outcome  Outcome `json:"outcome"`
+	Index    int     `json:"index"` // the signed index 3^(K-1)*r[0]+...+r[K-1]-(3^K-1)/2
+	Coin     *int    `json:"coin,omitempty"`
+	Weight   *Weight `json:"weight,omitempty"`
+	Possible bool    `json:"possible"`
+}
+
+// Answer a row for each of the 3^K outcomes of the solution, in increasing order of outcome,
+// independent of the compression and flip that Decide uses to index the Coins and Weights.
+func (s *Solution) OutcomeTable() ([]OutcomeRow, error) {
+	var r *Solution
+	var err error
+	if s.flags&REVERSED == 0 {
+		if r, err = s.Reverse(); err != nil {
+			return nil, err
+		}
+	} else {
+		r = s
+	}
+
+	k := len(r.Weighings)
+	rows := make([]OutcomeRow, pow3(k))
+	for u, _ := range rows {
+		o := outcome(k, u)
+		rows[u] = OutcomeRow{Outcome: o, Index: index(o)}
+		if r.possible(o) {
+			c, w, _ := r.lookup(o)
+			rows[u].Coin, rows[u].Weight, rows[u].Possible = &c, &w, true
+		}
+	}
+	return rows, nil
+}
+
+// Answer the verdict of each possible outcome of the solution, in increasing order of
+// outcome. A verdict with weight Equal identifies the coin but not its weight.
+func (s *Solution) Verdicts() ([]Verdict, error) {
+	rows, err := s.OutcomeTable()
+	if err != nil {
+		return nil, err
+	}
+	verdicts := []Verdict{}
+	for _, row := range rows {
+		if row.Possible {
+			verdicts = append(verdicts, Verdict{Outcome: row.Outcome, Coin: *row.Coin, Weight: *row.Weight})
+		}
+	}
+	return verdicts, nil
+}
+
+func (v Verdict) direction() string {
+	if v.Weight == Equal {
+		return "light or heavy"
+	}
+	return v.Weight.String()
+}
+
+func joinCoins(coins []int) string {
+	s := make([]string, len(coins))
+	for i, c := range coins {
+		s[i] = fmt.Sprintf("%d", c)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Markdown renders the solution as two Markdown tables: one with the pans of each
+// weighing and one with the coin and relative weight implied by each possible outcome.
+func (s *Solution) Markdown() (string, error) {
+	verdicts, err := s.Verdicts()
+	if err != nil {
+		return "", err
+	}
+	z := s.GetZeroCoin()
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "| Weighing | Left | Right |\n")
+	fmt.Fprintf(b, "|---:|---|---|\n")
+	for j, w := range s.Weighings {
+		fmt.Fprintf(b, "| %d | %s | %s |\n", j+1, joinCoins(w.Left().AsCoins(z)), joinCoins(w.Right().AsCoins(z)))
+	}
+	fmt.Fprintf(b, "\n")
+	fmt.Fprintf(b, "| Outcome | Coin | Weight |\n")
+	fmt.Fprintf(b, "|---|---:|---|\n")
+	for _, v := range verdicts {
+		fmt.Fprintf(b, "| %v | %d | %s |\n", v.Outcome, v.Coin, v.direction())
+	}
+	return b.String(), nil
+}
+
+// LaTeX renders the solution as two LaTeX tabular environments: one with the pans of each
+// weighing and one with the coin and relative weight implied by each possible outcome.
+func (s *Solution) LaTeX() (string, error) {
+	verdicts, err := s.Verdicts()
+	if err != nil {
+		return "", err
+	}
+	z := s.GetZeroCoin()
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "\\begin{tabular}{rll}\n\\hline\n")
+	fmt.Fprintf(b, "Weighing & Left & Right \\\\\n\\hline\n")
+	for j, w := range s.Weighings {
+		fmt.Fprintf(b, "%d & %s & %s \\\\\n", j+1, joinCoins(w.Left().AsCoins(z)), joinCoins(w.Right().AsCoins(z)))
+	}
+	fmt.Fprintf(b, "\\hline\n\\end{tabular}\n\n")
+	fmt.Fprintf(b, "\\begin{tabular}{lrl}\n\\hline\n")
+	fmt.Fprintf(b, "Outcome & Coin & Weight \\\\\n\\hline\n")
+	for _, v := range verdicts {
+		fmt.Fprintf(b, "\\texttt{%v} & %d & %s \\\\\n", v.Outcome, v.Coin, v.direction())
+	}
+	fmt.Fprintf(b, "\\hline\n\\end{tabular}\n")
+	return b.String(), nil
+}
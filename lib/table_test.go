@@ -0,0 +1,40 @@
+package lib
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	md, err := load(t, "canonical.json").Markdown()
+	if err != nil {
+		t.Fatalf("markdown: %v", err)
+	}
+	if c := strings.Count(md, "\n"); c != 2+3+1+2+24 {
+		t.Fatalf("assertion failed: was: %d expected: %d", c, 2+3+1+2+24)
+	}
+	if !strings.Contains(md, "| LLE | 6 | heavy |") {
+		t.Fatalf("expected LLE to decide 6 heavy: %s", md)
+	}
+}
+
+func TestOutcomeTable(t *testing.T) {
+	for _, name := range []string{"canonical.json", "needsflip.json"} {
+		rows, err := load(t, name).OutcomeTable()
+		if err != nil {
+			t.Fatalf("outcomes: %s: %v", name, err)
+		}
+		possible := 0
+		for i, row := range rows {
+			if row.Index != i-13 {
+				t.Fatalf("assertion failed: was: %d expected: %d", row.Index, i-13)
+			}
+			if row.Possible {
+				possible++
+			}
+		}
+		if len(rows) != 27 || possible != 24 {
+			t.Fatalf("assertion failed: was: %d, %d expected: %d, %d", len(rows), possible, 27, 24)
+		}
+	}
+}
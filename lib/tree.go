@@ -0,0 +1,166 @@
+package lib
+
+import (
+	"fmt"
+)
+
+// A DecisionTree describes an adaptive strategy as data. Each node of the tree either
+// holds a weighing and a child for each of the light, equal and heavy results of
+// that weighing or is a leaf which holds the verdict: the counterfeit coin and its
+// relative weight. A missing child denotes an outcome that is not possible.
+//
+// The zero coin is only significant at the root of the tree.
+type DecisionTree struct {
+	ZeroCoin *int          `json:"zero-coin,omitempty"`
+	Weighing *[2][]int     `json:"weighing,omitempty"`
+	Light    *DecisionTree `json:"light,omitempty"`
+	Equal    *DecisionTree `json:"equal,omitempty"`
+	Heavy    *DecisionTree `json:"heavy,omitempty"`
+	Coin     *int          `json:"coin,omitempty"`
+	Weight   *Weight       `json:"weight,omitempty"`
+}
+
+func (t *DecisionTree) GetZeroCoin() int {
+	if t.ZeroCoin == nil {
+		return 1
+	} else {
+		return *t.ZeroCoin
+	}
+}
+
+// Answer true if the receiver is a leaf of the tree.
+func (t *DecisionTree) IsLeaf() bool {
+	return t.Weighing == nil
+}
+
+// Answer the child of the receiver that corresponds to the specified result.
+func (t *DecisionTree) Child(w Weight) *DecisionTree {
+	switch w {
+	case Light:
+		return t.Light
+	case Equal:
+		return t.Equal
+	case Heavy:
+		return t.Heavy
+	default:
+		panic(fmt.Errorf("illegal argument: w: %v", w))
+	}
+}
+
+// Answer the maximum number of weighings required by the tree.
+func (t *DecisionTree) Depth() int {
+	if t == nil || t.IsLeaf() {
+		return 0
+	}
+	d := 0
+	for _, c := range []*DecisionTree{t.Light, t.Equal, t.Heavy} {
+		if cd := c.Depth(); cd > d {
+			d = cd
+		}
+	}
+	return d + 1
+}
+
+// Answer the number of coins of the problem, derived from the highest numbered
+// coin that appears in any weighing or verdict of the tree.
+func (t *DecisionTree) CoinCount() int {
+	z := t.GetZeroCoin()
+	n := 0
+	var visit func(t *DecisionTree)
+	visit = func(t *DecisionTree) {
+		if t == nil {
+			return
+		}
+		coins := []int{}
+		if t.Coin != nil {
+			coins = append(coins, *t.Coin)
+		}
+		if t.Weighing != nil {
+			coins = append(append(coins, t.Weighing[0]...), t.Weighing[1]...)
+		}
+		for _, c := range coins {
+			if c-z+1 > n {
+				n = c - z + 1
+			}
+		}
+		visit(t.Light)
+		visit(t.Equal)
+		visit(t.Heavy)
+	}
+	visit(t)
+	return n
+}
+
+// Decide which coin is counterfeit and what its relative weight is by following
+// the branches of the tree selected by the results of each weighing.
+// Decide is a Candidate.
+func (t *DecisionTree) Decide(scale Scale) (int, Weight) {
+	scale.SetZeroCoin(t.GetZeroCoin())
+	n := t
+	for !n.IsLeaf() {
+		w := scale.Weigh(n.Weighing[0], n.Weighing[1])
+		if c := n.Child(w); c == nil {
+			panic(fmt.Errorf("decision tree has no branch for result: %v of weighing: %v", w, *n.Weighing))
+		} else {
+			n = c
+		}
+	}
+	if n.Coin == nil || n.Weight == nil {
+		panic(fmt.Errorf("decision tree has a leaf without a verdict"))
+	}
+	return *n.Coin, *n.Weight
+}
+
+// Convert the solution into an equivalent decision tree. The solution
+// is reversed first, if necessary.
+func (s *Solution) DecisionTree() (*DecisionTree, error) {
+	var r *Solution
+	var err error
+	if s.flags&REVERSED == 0 {
+		if r, err = s.Reverse(); err != nil {
+			return nil, err
+		}
+	} else {
+		r = s
+	}
+
+	z := r.GetZeroCoin()
+	results := make([]Weight, len(r.Weighings))
+
+	var build func(depth int) *DecisionTree
+	build = func(depth int) *DecisionTree {
+		if depth == len(r.Weighings) {
+			if !r.possible(results) {
+				return nil
+			}
+			coin, weight, _ := r.lookup(results)
+			return &DecisionTree{
+				Coin:   pi(coin),
+				Weight: &weight,
+			}
+		}
+		w := r.Weighings[depth]
+		t := &DecisionTree{
+			Weighing: &[2][]int{w.Left().AsCoins(z), w.Right().AsCoins(z)},
+		}
+		children := [3]*DecisionTree{}
+		for _, result := range []Weight{Light, Equal, Heavy} {
+			results[depth] = result
+			children[result] = build(depth + 1)
+		}
+		if children[Light] == nil && children[Equal] == nil && children[Heavy] == nil {
+			return nil
+		}
+		t.Light, t.Equal, t.Heavy = children[Light], children[Equal], children[Heavy]
+		return t
+	}
+
+	t := build(0)
+	if t == nil {
+		return nil, fmt.Errorf("illegal state: solution has no possible outcomes")
+	}
+	if z != ONE_BASED {
+		t.ZeroCoin = pi(z)
+	}
+	return t, nil
+}
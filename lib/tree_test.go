@@ -0,0 +1,75 @@
+package lib
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecisionTreeFromSolution(t *testing.T) {
+	for _, name := range []string{"needsflip.json", "simple.json", "39-coins.json"} {
+		s := load(t, name)
+		tree, err := s.DecisionTree()
+		if err != nil {
+			t.Fatalf("tree: %s: %v", name, err)
+		}
+		buf, err := json.Marshal(tree)
+		if err != nil {
+			t.Fatalf("marshal: %s: %v", name, err)
+		}
+		decoded := &DecisionTree{}
+		if err := json.Unmarshal(buf, decoded); err != nil {
+			t.Fatalf("unmarshal: %s: %v", name, err)
+		}
+		if errors := TestAllN(decoded.CoinCount(), decoded.Depth(), decoded.Decide); len(errors) != 0 {
+			t.Fatalf("unexpected failures: %s: %v", name, errors)
+		}
+	}
+}
+
+func TestAdaptiveDecisionTree(t *testing.T) {
+	// weigh 1 against 2 and then, only if they balance, 1 against 3.
+	tree := &DecisionTree{}
+	if err := json.Unmarshal([]byte(`{
+		"weighing":[[1],[2]],
+		"light":{"weighing":[[1],[3]],"light":{"coin":1,"weight":0},"equal":{"coin":2,"weight":2}},
+		"equal":{"weighing":[[1],[3]],"light":{"coin":3,"weight":2},"heavy":{"coin":3,"weight":0}},
+		"heavy":{"weighing":[[1],[3]],"heavy":{"coin":1,"weight":2},"equal":{"coin":2,"weight":0}}
+	}`), tree); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if errors := TestAllN(3, 2, tree.Decide); len(errors) != 0 {
+		t.Fatalf("unexpected failures: %v", errors)
+	}
+	tree.Equal.Light = nil
+	if errors := TestAllN(3, 2, tree.Decide); len(errors) != 1 {
+		t.Fatalf("expected exactly one failure: %v", errors)
+	}
+}
+
+func TestDot(t *testing.T) {
+	dot, err := load(t, "needsflip.json").Dot()
+	if err != nil {
+		t.Fatalf("dot: %v", err)
+	}
+	if c := strings.Count(dot, "shape=ellipse"); c != 24 {
+		t.Fatalf("assertion failed: was: %d expected: %d", c, 24)
+	}
+	if c := strings.Count(dot, "impossible"); c != 3 {
+		t.Fatalf("assertion failed: was: %d expected: %d", c, 3)
+	}
+}
+
+func TestIncidenceDotKeepsSolution(t *testing.T) {
+	s := &Solution{}
+	if err := json.Unmarshal([]byte(`{"weighings":[[[1,10,11,12],[4,5,6,7]],[[12,7,8,2],[9,10,11,6]],[[3,10,8,9],[11,12,4,5]]]}`), s); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	s.DecodeJSON()
+	if _, err := s.IncidenceDot(); err != nil {
+		t.Fatalf("dot: %v", err)
+	}
+	if s.flags != 0 || len(s.Failures) != 0 {
+		t.Fatalf("assertion failed: was: %d, %v expected: 0, []", s.flags, s.Failures)
+	}
+}
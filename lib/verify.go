@@ -0,0 +1,28 @@
+package lib
+
+import (
+	"fmt"
+)
+
+// The number of solution numbers, 12!*176. Every N in [0, MAX_N) is
+// assumed to decode to a distinct valid solution.
+const MAX_N uint = 479001600 * 176
+
+// Verify that the solution decoded from n is a valid solution and that the
+// number derived from an analysis of its weighings alone is n.
+func VerifyN(n uint) error {
+	s, err := DecodeSolution(n)
+	if err != nil {
+		return fmt.Errorf("decode: %v", err)
+	}
+	r := s.Reset()
+	if !r.IsValid() {
+		return fmt.Errorf("invalid solution: %v", r)
+	}
+	if m, err := r.N(); err != nil {
+		return fmt.Errorf("N: %v", err)
+	} else if m != n {
+		return fmt.Errorf("N: was: %d expected: %d", m, n)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestVerifyN(t *testing.T) {
+	for n := uint(0); n < 176; n++ {
+		for _, p := range []uint{0, 1, 479001599} {
+			if err := VerifyN(p*176 + n); err != nil {
+				t.Fatalf("verify: %d: %v", p*176+n, err)
+			}
+		}
+	}
+}
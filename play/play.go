@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/jonseymour/12coins/lib"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A turn records one use of the scale.
+type turn struct {
+	left     []int
+	right    []int
+	result   lib.Weight
+	solvable bool // true if the counterfeit coin could still be found with certainty
+}
+
+// What is known about each coin: whether it may still be light or heavy.
+type game struct {
+	coins      int
+	weighings  int
+	oracle     *lib.Oracle
+	light      []bool
+	heavy      []bool
+	transcript []turn
+}
+
+func newGame(coins int, weighings int, r *rand.Rand) *game {
+	w := lib.Light
+	if r.Intn(2) == 1 {
+		w = lib.Heavy
+	}
+	g := &game{
+		coins:     coins,
+		weighings: weighings,
+		oracle:    lib.NewOracleN(coins, weighings, 1+r.Intn(coins), w, lib.ONE_BASED),
+		light:     make([]bool, coins),
+		heavy:     make([]bool, coins),
+	}
+	for i := 0; i < coins; i++ {
+		g.light[i] = true
+		g.heavy[i] = true
+	}
+	return g
+}
+
+// Answer true if the counterfeit coin can be found with certainty in the remaining weighings.
+func (g *game) solvable() bool {
+	unknown, light, heavy, genuine := 0, 0, 0, 0
+	for i := 0; i < g.coins; i++ {
+		switch {
+		case g.light[i] && g.heavy[i]:
+			unknown++
+		case g.light[i]:
+			light++
+		case g.heavy[i]:
+			heavy++
+		default:
+			genuine++
+		}
+	}
+	return lib.Solvable(unknown, light, heavy, genuine, g.weighings-len(g.transcript))
+}
+
+// Weigh the coins with the oracle. Answers the error reported by the oracle if the
+// weighing is illegal.
+func (g *game) weigh(left []int, right []int) (result lib.Weight, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = e.(error)
+		}
+	}()
+	result = g.oracle.Weigh(left, right)
+
+	on := make([]lib.Weight, g.coins)
+	for i, _ := range on {
+		on[i] = lib.Equal
+	}
+	for _, c := range left {
+		on[c-1] = lib.Heavy
+	}
+	for _, c := range right {
+		on[c-1] = lib.Light
+	}
+	for i, _ := range on {
+		// a coin is only still suspect if its placement can explain the result
+		if result == lib.Equal {
+			if on[i] != lib.Equal {
+				g.light[i], g.heavy[i] = false, false
+			}
+		} else if on[i] == lib.Equal {
+			g.light[i], g.heavy[i] = false, false
+		} else if on[i] == result {
+			g.light[i] = false
+		} else {
+			g.heavy[i] = false
+		}
+	}
+	g.transcript = append(g.transcript, turn{left: left, right: right, result: result})
+	g.transcript[len(g.transcript)-1].solvable = g.solvable()
+	return result, nil
+}
+
+// Parse a weighing such as "1 2 3 4 v 5 6 7 8".
+func parseWeighing(fields []string) ([]int, []int, error) {
+	result := [2][]int{{}, {}}
+	p := 0
+	for _, f := range fields {
+		if f == "v" {
+			p++
+			if p > 1 {
+				return nil, nil, fmt.Errorf("expected a weighing such as: 1 2 3 4 v 5 6 7 8")
+			}
+			continue
+		}
+		c, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid coin: %s", f)
+		}
+		result[p] = append(result[p], c)
+	}
+	return result[0], result[1], nil
+}
+
+// Answer true if the fields describe a weighing rather than an answer.
+func isWeighing(fields []string) bool {
+	for _, f := range fields {
+		if f == "v" {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse an answer such as "5 heavy" or "5 l".
+func parseAnswer(line string) (int, lib.Weight, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return 0, lib.Equal, fmt.Errorf("expected an answer such as: 5 heavy")
+	}
+	c, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, lib.Equal, fmt.Errorf("invalid coin: %s", fields[0])
+	}
+	switch strings.ToLower(fields[1]) {
+	case "l", "light":
+		return c, lib.Light, nil
+	case "h", "heavy":
+		return c, lib.Heavy, nil
+	default:
+		return 0, lib.Equal, fmt.Errorf("invalid weight: %s", fields[1])
+	}
+}
+
+func describe(w lib.Weight) string {
+	switch w {
+	case lib.Light:
+		return "the left pan is lighter"
+	case lib.Heavy:
+		return "the left pan is heavier"
+	default:
+		return "the pans balance"
+	}
+}
+
+// Play one game, reading moves from in and writing to out.
+func (g *game) play(in io.Reader, out io.Writer) {
+	scanner := bufio.NewScanner(in)
+	fmt.Fprintf(out, "One of coins 1 to %d is counterfeit. Find it with at most %d weighings.\n", g.coins, g.weighings)
+	fmt.Fprintf(out, "Weigh with: 1 2 3 4 v 5 6 7 8. Answer with: 5 heavy.\n")
+	optimal := g.solvable()
+
+	coin, weight := 0, lib.Equal
+	answered := false
+	for !answered {
+		if len(g.transcript) < g.weighings {
+			fmt.Fprintf(out, "weighing %d> ", len(g.transcript)+1)
+		} else {
+			fmt.Fprintf(out, "answer> ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if fields := strings.Fields(line); isWeighing(fields) {
+			if len(g.transcript) == g.weighings {
+				fmt.Fprintf(out, "no weighings remain\n")
+				continue
+			}
+			left, right, err := parseWeighing(fields)
+			if err != nil {
+				fmt.Fprintf(out, "error: %v\n", err)
+				continue
+			}
+			if r, err := g.weigh(left, right); err != nil {
+				fmt.Fprintf(out, "illegal weighing: %v\n", err)
+			} else {
+				fmt.Fprintf(out, "%s\n", describe(r))
+			}
+			continue
+		}
+		var err error
+		if coin, weight, err = parseAnswer(line); err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+			continue
+		}
+		answered = true
+	}
+
+	fmt.Fprintf(out, "\ntranscript:\n")
+	for i, t := range g.transcript {
+		fmt.Fprintf(out, "%d: %v v %v: %s\n", i+1, t.left, t.right, describe(t.result))
+	}
+	c, w := g.oracle.Reveal()
+	fmt.Fprintf(out, "the counterfeit coin was %d (%v)\n", c, w)
+	if answered && c == coin && w == weight {
+		fmt.Fprintf(out, "correct!\n")
+	} else if answered {
+		fmt.Fprintf(out, "wrong: you answered %d (%v)\n", coin, weight)
+	}
+
+	if !optimal {
+		fmt.Fprintf(out, "no optimal strategy existed: no strategy can always find the coin\n")
+		return
+	}
+	fmt.Fprintf(out, "an optimal strategy existed")
+	for i, t := range g.transcript {
+		if !t.solvable {
+			fmt.Fprintf(out, ", but after weighing %d the coin could no longer be found with certainty", i+1)
+			break
+		}
+	}
+	fmt.Fprintf(out, "\n")
+}
+
+func main() {
+	coins := 12
+	weighings := 3
+	seed := time.Now().UnixNano()
+
+	flag.IntVar(&coins, "coins", 12, "The number of coins.")
+	flag.IntVar(&weighings, "weighings", 3, "The number of weighings allowed.")
+	flag.Int64Var(&seed, "seed", seed, "The seed used to hide the counterfeit coin.")
+	flag.Parse()
+
+	// more coins than lib.MaxCoins(weighings) are allowed: the game then reports that
+	// no optimal strategy existed.
+	if coins < 1 {
+		fmt.Fprintf(os.Stderr, "error: -coins must be at least 1: %d\n", coins)
+		os.Exit(1)
+	}
+	if weighings < 1 {
+		fmt.Fprintf(os.Stderr, "error: -weighings must be at least 1: %d\n", weighings)
+		os.Exit(1)
+	}
+
+	newGame(coins, weighings, rand.New(rand.NewSource(seed))).play(os.Stdin, os.Stdout)
+}
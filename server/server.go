@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/jonseymour/12coins/lib"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// An apiError describes why an operation failed.
+type apiError struct {
+	Operation string        `json:"operation"`
+	Message   string        `json:"message"`
+	Failures  []lib.Failure `json:"failures,omitempty"`
+}
+
+// The result of applying an operation to one input. Exactly one of Solution, N,
+// Valid or Format is set, unless Error is set.
+type result struct {
+	Solution *lib.Solution `json:"solution,omitempty"`
+	N        *uint         `json:"N,omitempty"`
+	Valid    *bool         `json:"valid,omitempty"`
+	Format   *string       `json:"format,omitempty"`
+	Error    *apiError     `json:"error,omitempty"`
+}
+
+// An operation applies a transformation to a decoded solution.
+type operation func(s *lib.Solution) result
+
+var operations = map[string]operation{
+	"reverse":   transform("reverse", (*lib.Solution).Reverse),
+	"flip":      transform("flip", (*lib.Solution).Flip),
+	"relabel":   transform("relabel", (*lib.Solution).Relabel),
+	"groupings": transform("groupings", (*lib.Solution).Groupings),
+	"structure": transform("structure", (*lib.Solution).AnalyseStructure),
+	"canonical": transform("canonical", (*lib.Solution).Canonical),
+	"encode": func(s *lib.Solution) result {
+		if n, err := s.N(); err != nil {
+			return failure("encode", err, nil)
+		} else {
+			return result{N: &n}
+		}
+	},
+	"validate": func(s *lib.Solution) result {
+		r, err := s.Reverse()
+		valid := err == nil
+		return result{Valid: &valid, Error: errorOf("validate", err, r)}
+	},
+	"format": func(s *lib.Solution) result {
+		f := s.Format()
+		return result{Format: &f}
+	},
+}
+
+// The operations that start from a fresh analysis of the weighings, as the corresponding
+// modes of tools do. The other operations use the solution as it was posted.
+var resets = map[string]bool{
+	"reverse":   true,
+	"flip":      true,
+	"relabel":   true,
+	"groupings": true,
+	"structure": true,
+	"canonical": true,
+	"encode":    true,
+	"validate":  true,
+}
+
+// Adapt a method of lib.Solution to an operation.
+func transform(name string, f func(s *lib.Solution) (*lib.Solution, error)) operation {
+	return func(s *lib.Solution) result {
+		if r, err := f(s); err != nil {
+			return failure(name, err, r)
+		} else {
+			r.Encode()
+			return result{Solution: r}
+		}
+	}
+}
+
+func errorOf(name string, err error, s *lib.Solution) *apiError {
+	if err == nil {
+		return nil
+	}
+	e := &apiError{Operation: name, Message: err.Error()}
+	if s != nil {
+		e.Failures = s.Failures
+	}
+	return e
+}
+
+func failure(name string, err error, s *lib.Solution) result {
+	return result{Error: errorOf(name, err, s)}
+}
+
+// Apply the named operation to one input, which is a solution or, for decode, a number.
+// A panic of the library is answered as the error of the operation.
+func apply(name string, input json.RawMessage) (r result) {
+	defer func() {
+		if e := recover(); e != nil {
+			r = failure(name, fmt.Errorf("%v", e), nil)
+		}
+	}()
+	if name == "decode" {
+		var n uint
+		if err := json.Unmarshal(input, &n); err != nil {
+			return failure(name, err, nil)
+		}
+		if s, err := lib.DecodeSolution(n); err != nil {
+			return failure(name, err, nil)
+		} else {
+			s.Encode()
+			return result{Solution: s}
+		}
+	}
+
+	s := &lib.Solution{}
+	if err := json.Unmarshal(input, s); err != nil {
+		return failure(name, err, nil)
+	}
+	s.DecodeJSON()
+	if resets[name] {
+		s = s.Reset()
+	}
+	return operations[name](s)
+}
+
+// Write a JSON response with the specified status code.
+func respond(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Handle a POST to /{operation}. The body is either a single input, which is answered
+// with a single result, or a JSON array of inputs, which is answered with an array of
+// results in the same order.
+func handle(w http.ResponseWriter, req *http.Request) {
+	name := strings.Trim(req.URL.Path, "/")
+	if _, ok := operations[name]; !ok && name != "decode" {
+		respond(w, http.StatusNotFound, result{Error: &apiError{Operation: name, Message: "unknown operation"}})
+		return
+	}
+	if req.Method != http.MethodPost {
+		respond(w, http.StatusMethodNotAllowed, result{Error: &apiError{Operation: name, Message: "use POST"}})
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		respond(w, http.StatusBadRequest, failure(name, err, nil))
+		return
+	}
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		inputs := []json.RawMessage{}
+		if err := json.Unmarshal(trimmed, &inputs); err != nil {
+			respond(w, http.StatusBadRequest, failure(name, err, nil))
+			return
+		}
+		results := make([]result, len(inputs))
+		for i, input := range inputs {
+			results[i] = apply(name, input)
+		}
+		respond(w, http.StatusOK, results)
+		return
+	}
+
+	r := apply(name, json.RawMessage(body))
+	if r.Error != nil && r.Valid == nil {
+		respond(w, http.StatusUnprocessableEntity, r)
+	} else {
+		respond(w, http.StatusOK, r)
+	}
+}
+
+// Answer the handler that serves every operation.
+func newHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handle)
+	return mux
+}
+
+func main() {
+	listen := "localhost:8080"
+	flag.StringVar(&listen, "listen", "localhost:8080", "The address on which to serve the operations.")
+	flag.Parse()
+
+	fmt.Printf("listening on %s\n", listen)
+	log.Fatal(http.ListenAndServe(listen, newHandler()))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jonseymour/12coins/lib"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func post(t *testing.T, path string, body string) (int, []byte) {
+	server := httptest.NewServer(newHandler())
+	defer server.Close()
+	resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	defer resp.Body.Close()
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return resp.StatusCode, buf
+}
+
+func TestBatchValidate(t *testing.T) {
+	code, body := post(t, "/validate", `[
+		{"weighings":[[[1,10,11,12],[4,5,6,7]],[[7,8,9,12],[2,6,10,11]],[[3,5,8,10],[4,9,11,12]]]},
+		{"weighings":[[[1,10,11,12],[4,5,6,7]],[[12,7,8,2],[9,10,11,6]],[[3,10,8,9],[11,12,4,5]]]}
+	]`)
+	if code != http.StatusOK {
+		t.Fatalf("assertion failed: was: %d expected: %d", code, http.StatusOK)
+	}
+	results := []result{}
+	if err := json.Unmarshal(body, &results); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(results) != 2 || !*results[0].Valid || *results[1].Valid || len(results[1].Error.Failures) == 0 {
+		t.Fatalf("unexpected results: %s", body)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if code, _ := post(t, "/reverse", `{"weighings":[[[1,2],[3]]]}`); code != http.StatusUnprocessableEntity {
+		t.Fatalf("assertion failed: was: %d expected: %d", code, http.StatusUnprocessableEntity)
+	}
+	if code, _ := post(t, "/unknown", `{}`); code != http.StatusNotFound {
+		t.Fatalf("assertion failed: was: %d expected: %d", code, http.StatusNotFound)
+	}
+	if code, _ := post(t, "/decode", `[`); code != http.StatusBadRequest {
+		t.Fatalf("assertion failed: was: %d expected: %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestFormatKeepsAnalysis(t *testing.T) {
+	// a reversed solution is formatted as posted, as tools -format does
+	code, body := post(t, "/format", `{"weighings":[[[1],[2]],[[1],[3]]],"coins":[1,3,2,1,2,3,1],"weights":[1,0,0,1,2,2,1],"flip":1}`)
+	if code != http.StatusOK {
+		t.Fatalf("assertion failed: was: %d expected: %d", code, http.StatusOK)
+	}
+	r := result{}
+	if err := json.Unmarshal(body, &r); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if r.Format == nil || !strings.Contains(*r.Format, `"coins"`) || !strings.Contains(*r.Format, `"flip"`) {
+		t.Fatalf("unexpected result: %s", body)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	operations["panic"] = func(s *lib.Solution) result {
+		panic(fmt.Errorf("illegal state"))
+	}
+	defer delete(operations, "panic")
+	code, body := post(t, "/panic", `{"weighings":[[[1],[2]]]}`)
+	if code != http.StatusUnprocessableEntity {
+		t.Fatalf("assertion failed: was: %d expected: %d", code, http.StatusUnprocessableEntity)
+	}
+	r := result{}
+	if err := json.Unmarshal(body, &r); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if r.Error == nil || r.Error.Operation != "panic" || r.Error.Message != "illegal state" {
+		t.Fatalf("unexpected result: %s", body)
+	}
+}
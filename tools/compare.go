@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jonseymour/12coins/lib"
+	"os"
+)
+
+// The result of comparing two solutions.
+type comparison struct {
+	Equivalent bool          `json:"equivalent"`
+	Symmetry   *lib.Symmetry `json:"symmetry,omitempty"`
+}
+
+// Load a solution from the specified file.
+func loadSolution(file string) (*lib.Solution, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s := &lib.Solution{}
+	if err := json.NewDecoder(f).Decode(s); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	s.DecodeJSON()
+	return s.Reset(), nil
+}
+
+// Compare the solutions in two files and report whether they are equivalent and, if
+// so, the symmetry that maps the first to the second. Answers the exit code, which is
+// 0 only if the solutions are equivalent.
+func compare(files []string) int {
+	if len(files) != 2 {
+		fmt.Fprintf(os.Stderr, "usage: tools -compare a.json b.json\n")
+		return 2
+	}
+	solutions := make([]*lib.Solution, len(files))
+	for i, file := range files {
+		var err error
+		if solutions[i], err = loadSolution(file); err != nil {
+			fmt.Fprintf(os.Stderr, "error: compare: %v\n", err)
+			return 2
+		}
+	}
+	result := comparison{}
+	if y, ok := lib.Equivalent(solutions[0], solutions[1]); ok {
+		result.Equivalent = true
+		result.Symmetry = &y
+	}
+	json.NewEncoder(os.Stdout).Encode(&result)
+	if !result.Equivalent {
+		return 1
+	}
+	return 0
+}
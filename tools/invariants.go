@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"github.com/jonseymour/12coins/lib"
+	"os"
+)
+
+// Check the assumed truths of structure.go against every valid solution of the 12 coins
+// problem found by Search and report, for each, whether it passes and up to max of its
+// counterexamples.
+//
+// Answers the exit code.
+func invariants(max int) int {
+	solutions := []*lib.Solution{}
+	lib.Search(12, 3, func(s *lib.Solution) {
+		solutions = append(solutions, s)
+	})
+
+	code := 0
+	for _, r := range lib.CheckInvariants(solutions) {
+		if r.Pass {
+			fmt.Fprintf(os.Stdout, "PASS\t%s (%d solutions)\n", r.Invariant, r.Checked)
+			continue
+		}
+		code = 1
+		fmt.Fprintf(os.Stdout, "FAIL\t%s (%d of %d solutions)\n", r.Invariant, len(r.Counterexamples), r.Checked)
+		for i, ce := range r.Counterexamples {
+			if i == max {
+				break
+			}
+			if ce.Solution != nil {
+				fmt.Fprintf(os.Stdout, "\t%s: %v\n", ce.Reason, ce.Solution.Reset())
+			} else {
+				fmt.Fprintf(os.Stdout, "\t%s\n", ce.Reason)
+			}
+		}
+	}
+	return code
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/jonseymour/12coins/lib"
+	"io"
+	"strconv"
+)
+
+// Writes the full outcome tables of a stream of solutions as CSV, with one header, or as
+// JSON lines. Each row records the position of its solution in the stream, counting from 0.
+type outcomeWriter struct {
+	out       io.Writer
+	format    string
+	csv       *csv.Writer
+	solutions int
+}
+
+// A row of the outcome table of the solution at the specified position in the stream.
+type outcomeRow struct {
+	Solution int `json:"solution"`
+	lib.OutcomeRow
+}
+
+func newOutcomeWriter(out io.Writer, format string) *outcomeWriter {
+	return &outcomeWriter{out: out, format: format}
+}
+
+// Write the full outcome table of the next solution of the stream.
+func (ow *outcomeWriter) write(s *lib.Solution) error {
+	solution := ow.solutions
+	ow.solutions++
+	rows, err := s.OutcomeTable()
+	if err != nil {
+		return err
+	}
+	switch ow.format {
+	case "json":
+		encoder := json.NewEncoder(ow.out)
+		for _, row := range rows {
+			if err := encoder.Encode(&outcomeRow{Solution: solution, OutcomeRow: row}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		if ow.csv == nil {
+			ow.csv = csv.NewWriter(ow.out)
+			ow.csv.Write([]string{"solution", "outcome", "index", "coin", "weight", "possible"})
+		}
+		for _, row := range rows {
+			// the weight is numbered as in the JSON lines
+			coin, weight := "", ""
+			if row.Possible {
+				coin = strconv.Itoa(*row.Coin)
+				weight = strconv.Itoa(int(*row.Weight))
+			}
+			ow.csv.Write([]string{strconv.Itoa(solution), row.Outcome.String(), strconv.Itoa(row.Index), coin, weight, strconv.FormatBool(row.Possible)})
+		}
+		ow.csv.Flush()
+		return ow.csv.Error()
+	default:
+		return fmt.Errorf("unknown outcome format: %s", ow.format)
+	}
+}
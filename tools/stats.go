@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jonseymour/12coins/lib"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Aggregate counts over a stream of solutions.
+type stats struct {
+	Solutions  int            `json:"solutions"`
+	Valid      int            `json:"valid"`
+	Invalid    int            `json:"invalid"`
+	Structures map[string]int `json:"structures"` // the canonical structure of each valid 12 coins solution
+	S          map[uint]int   `json:"S"`
+	F          map[uint]int   `json:"F"`
+	Flip       map[string]int `json:"flip"`     // the weighing flipped by Reverse, or none
+	Profiles   map[string]int `json:"profiles"` // the number of coins on each pan of each weighing
+}
+
+func newStats() *stats {
+	return &stats{
+		Structures: map[string]int{},
+		S:          map[uint]int{},
+		F:          map[uint]int{},
+		Flip:       map[string]int{},
+		Profiles:   map[string]int{},
+	}
+}
+
+// Add the solution to the counts.
+func (st *stats) add(s *lib.Solution) {
+	st.Solutions++
+
+	profile := make([]string, len(s.Weighings))
+	for i, w := range s.Weighings {
+		profile[i] = fmt.Sprintf("%dv%d", w.Left().Size(), w.Right().Size())
+	}
+	st.Profiles[strings.Join(profile, " ")]++
+
+	r, err := s.Reverse()
+	if err != nil {
+		st.Invalid++
+		return
+	}
+	st.Valid++
+	if f, ok := r.FlippedWeighing(); ok {
+		st.Flip[fmt.Sprintf("%d", f)]++
+	} else {
+		st.Flip["none"]++
+	}
+
+	if a, err := r.AnalyseStructure(); err == nil && a.S != nil && a.F != nil {
+		st.Structures[lib.StructureName(*a.S)]++
+		st.S[*a.S]++
+		st.F[*a.F]++
+	}
+}
+
+// Write the counts as JSON or as a human-readable table.
+func (st *stats) write(out io.Writer, format string) error {
+	switch format {
+	case "json":
+		return json.NewEncoder(out).Encode(st)
+	case "text":
+		fmt.Fprintf(out, "solutions\t%d\n", st.Solutions)
+		fmt.Fprintf(out, "valid\t%d\n", st.Valid)
+		fmt.Fprintf(out, "invalid\t%d\n", st.Invalid)
+		section := func(title string, keys []string, counts []int) {
+			fmt.Fprintf(out, "\n%s\n", title)
+			for i, k := range keys {
+				fmt.Fprintf(out, "%s\t%d\n", k, counts[i])
+			}
+		}
+		keys, counts := byName(st.Structures)
+		section("structure", keys, counts)
+		keys, counts = byNumber(st.S)
+		section("S", keys, counts)
+		keys, counts = byNumber(st.F)
+		section("F", keys, counts)
+		keys, counts = byName(st.Flip)
+		section("flip", keys, counts)
+		keys, counts = byName(st.Profiles)
+		section("profile", keys, counts)
+		return nil
+	default:
+		return fmt.Errorf("unknown stats format: %s", format)
+	}
+}
+
+func byName(m map[string]int) ([]string, []int) {
+	keys := []string{}
+	for k, _ := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	counts := make([]int, len(keys))
+	for i, k := range keys {
+		counts[i] = m[k]
+	}
+	return keys, counts
+}
+
+func byNumber(m map[uint]int) ([]string, []int) {
+	numbers := []int{}
+	for k, _ := range m {
+		numbers = append(numbers, int(k))
+	}
+	sort.Ints(numbers)
+	keys := make([]string, len(numbers))
+	counts := make([]int, len(numbers))
+	for i, n := range numbers {
+		keys[i] = fmt.Sprintf("%d", n)
+		counts[i] = m[uint(n)]
+	}
+	return keys, counts
+}
@@ -5,7 +5,11 @@ import (
 	"flag"
 	"fmt"
 	"github.com/jonseymour/12coins/lib"
+	"io"
+	"math/rand"
 	"os"
+	"runtime"
+	"time"
 )
 
 func main() {
@@ -22,6 +26,41 @@ func main() {
 	decode := false
 	encode := false
 	format := false
+	tree := false
+	testTree := false
+	search := false
+	diagnose := false
+	classify := false
+	compareFiles := false
+	dot := false
+	svg := false
+	markdown := false
+	outcomes := false
+	binary := false
+	outcomeFormat := "json"
+	stats := false
+	random := 0
+	seed := int64(1)
+	randomStructure := ""
+	randomS := -1
+	randomF := -1
+	statsFormat := "text"
+	latex := false
+	incidence := false
+	repair := false
+	repairs := 1
+	depth := 3
+	coins := 12
+	weighings := 3
+	verifyAll := false
+	from := uint(0)
+	to := lib.MAX_N
+	workers := runtime.NumCPU()
+	chunk := uint(100000)
+	checkpointFile := ""
+	checkInvariants := false
+	counterexamples := 3
+	interval := time.Minute
 
 	flag.BoolVar(&reverse, "reverse", false, "Derive the coins and weights array from the weighings.")
 	flag.BoolVar(&flip, "flip", false, "Flip the weighings so that LLL is never a valid weighing.")
@@ -36,6 +75,41 @@ func main() {
 	flag.BoolVar(&decode, "decode", false, "Decode a number between 0 and 12!*176 and output the corresponding solution.")
 	flag.BoolVar(&encode, "encode", false, "Encode a solution as a number between 0 and 12!*176.")
 	flag.BoolVar(&format, "format", false, "Format each solution over multiple lines.")
+	flag.BoolVar(&tree, "tree", false, "Convert each solution into a decision tree.")
+	flag.BoolVar(&testTree, "test-tree", false, "Read decision trees and test each against all possibilities.")
+	flag.BoolVar(&classify, "classify", false, "Map each solution to the representative of its orbit under relabeling, weighing permutations and pan swaps and record the size of the orbit.")
+	flag.BoolVar(&binary, "binary", false, "With -encode, write the numbers as a binary stream of 40-bit records. -decode detects binary input itself.")
+	flag.BoolVar(&outcomes, "outcomes", false, "Output a row for every outcome of each solution with the position of the solution in the stream, the coin, weight, signed index and whether it is possible.")
+	flag.StringVar(&outcomeFormat, "outcome-format", "json", "The format of the outcome rows: json (one row per line) or csv.")
+	flag.BoolVar(&stats, "stats", false, "Count the valid and invalid solutions and tally their structures, S, F, flip and pan sizes.")
+	flag.StringVar(&statsFormat, "stats-format", "text", "The format of the counts: text or json.")
+	flag.BoolVar(&markdown, "markdown", false, "Render each solution as Markdown tables of its weighings and outcomes.")
+	flag.BoolVar(&latex, "latex", false, "Render each solution as LaTeX tables of its weighings and outcomes.")
+	flag.BoolVar(&svg, "svg", false, "Render the weighings of each solution as an SVG document.")
+	flag.BoolVar(&dot, "dot", false, "Render each solution as a Graphviz DOT graph of its decision tree.")
+	flag.BoolVar(&incidence, "incidence", false, "With -dot, render the incidence of coins and weighings coloured by groupings instead.")
+	flag.BoolVar(&compareFiles, "compare", false, "Compare the solutions in the two files named by the arguments and report the symmetry that maps one to the other.")
+	flag.BoolVar(&diagnose, "diagnose", false, "Report the ambiguous outcomes, unused outcomes and unweighed coins of each solution.")
+	flag.BoolVar(&repair, "repair", false, "Search for the valid solutions that need the fewest edits of each solution.")
+	flag.IntVar(&repairs, "k", 1, "The number of repairs to output for each solution.")
+	flag.IntVar(&depth, "depth", 3, "The largest number of edits considered by a repair.")
+	flag.BoolVar(&search, "search", false, "Search for all valid solutions, up to relabeling and pan order, instead of reading stdin.")
+	flag.IntVar(&random, "random", 0, "Draw this many valid solutions of the 12 coins problem uniformly at random instead of reading stdin.")
+	flag.Int64Var(&seed, "seed", 1, "The seed of the random solutions.")
+	flag.StringVar(&randomStructure, "random-structure", "", "With -random, only draw solutions with this canonical structure: ppp, qpp, prs, prt or qrs.")
+	flag.IntVar(&randomS, "random-S", -1, "With -random, only draw solutions with this value of S, between 0 and 21.")
+	flag.IntVar(&randomF, "random-F", -1, "With -random, only draw solutions with this value of F, between 0 and 7.")
+	flag.IntVar(&coins, "coins", 12, "The number of coins of the problem to search.")
+	flag.IntVar(&weighings, "weighings", 3, "The number of weighings of the problem to search.")
+	flag.BoolVar(&verifyAll, "verify", false, "Verify that every number in [from, to) decodes to a valid solution with the same number.")
+	flag.UintVar(&from, "from", 0, "The first number to verify. Must agree with the checkpoint, if one is resumed.")
+	flag.UintVar(&to, "to", lib.MAX_N, "The number after the last number to verify. Must agree with the checkpoint, if one is resumed.")
+	flag.IntVar(&workers, "workers", runtime.NumCPU(), "The number of concurrent verification workers.")
+	flag.UintVar(&chunk, "chunk", 100000, "The number of numbers verified by a worker at a time.")
+	flag.StringVar(&checkpointFile, "checkpoint", "", "The file used to checkpoint and resume verification.")
+	flag.DurationVar(&interval, "interval", time.Minute, "The interval between verification checkpoints.")
+	flag.BoolVar(&checkInvariants, "invariants", false, "Check the assumed truths about valid solutions against every solution found by search and report the counterexamples.")
+	flag.IntVar(&counterexamples, "counterexamples", 3, "The largest number of counterexamples reported for each invariant.")
 	flag.Parse()
 
 	if invalid && valid {
@@ -43,24 +117,120 @@ func main() {
 		os.Exit(1)
 	}
 
+	if binary && !encode {
+		fmt.Fprintf(os.Stderr, "error: -binary is only used with -encode\n")
+		os.Exit(1)
+	}
+
 	if flip {
 		reverse = false
 	}
 
-	reset = reset || flip || reverse || relabel || groupings || structure || canonical || valid || invalid || encode
+	if compareFiles {
+		os.Exit(compare(flag.Args()))
+	}
+
+	if checkInvariants {
+		os.Exit(invariants(counterexamples))
+	}
+
+	if verifyAll {
+		ranged := false
+		flag.Visit(func(f *flag.Flag) {
+			ranged = ranged || f.Name == "from" || f.Name == "to"
+		})
+		os.Exit(verify(from, to, ranged, workers, chunk, checkpointFile, interval))
+	}
+
+	reset = reset || flip || reverse || relabel || groupings || structure || canonical || valid || invalid || encode || diagnose || repair || classify || stats
 
 	structure = structure || encode
 
 	decoder := json.NewDecoder(os.Stdin)
 	encoder := json.NewEncoder(os.Stdout)
+	numbers := lib.NumberReader(nil)
+	if decode {
+		numbers = lib.NewNumberReader(os.Stdin)
+	}
+	numberWriter := lib.NewNumberWriter(os.Stdout)
+	defer numberWriter.Flush()
+
+	if testTree {
+		os.Exit(testTrees(decoder))
+	}
+
+	tally := newStats()
+	code := 0
+	outcomeTables := newOutcomeWriter(os.Stdout, outcomeFormat)
+
+	rng := rand.New(rand.NewSource(seed))
+	structures := []uint{}
+	if random < 0 {
+		fmt.Fprintf(os.Stderr, "error: random: the number of solutions must not be negative: %d\n", random)
+		os.Exit(1)
+	}
+	if randomStructure != "" {
+		var err error
+		if structures, err = lib.StructureClass(randomStructure); err != nil {
+			fmt.Fprintf(os.Stderr, "error: random: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if randomS >= 0 {
+		if randomS >= 22 {
+			fmt.Fprintf(os.Stderr, "error: random: S must be less than 22: %d\n", randomS)
+			os.Exit(1)
+		}
+		if randomStructure != "" && lib.StructureName(uint(randomS)) != randomStructure {
+			fmt.Fprintf(os.Stderr, "error: random: S %d does not encode the structure %s\n", randomS, randomStructure)
+			os.Exit(1)
+		}
+		structures = []uint{uint(randomS)}
+	}
+	flips := []uint{}
+	if randomF >= 0 {
+		if randomF >= 8 {
+			fmt.Fprintf(os.Stderr, "error: random: F must be less than 8: %d\n", randomF)
+			os.Exit(1)
+		}
+		flips = []uint{uint(randomF)}
+	}
+	drawn := 0
+
+	found := []*lib.Solution{}
+	if search {
+		lib.Search(coins, weighings, func(s *lib.Solution) {
+			found = append(found, s)
+		})
+	}
+
 	for {
 		var err error
 		ok := true
 		solution := &lib.Solution{}
 
-		if decode {
-			var n uint
-			if err := decoder.Decode(&n); err != nil {
+		if search {
+			if len(found) == 0 {
+				break
+			}
+			solution, found = found[0], found[1:]
+		} else if random > 0 {
+			if drawn == random {
+				break
+			}
+			drawn++
+			if solution, err = lib.RandomRestrictedSolution(rng, structures, flips); err != nil {
+				fmt.Fprintf(os.Stderr, "error: random: %v\n", err)
+				break
+			}
+		} else if decode {
+			n, err := numbers.Read()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				// a corrupt stream must fail the pipeline that reads it
+				fmt.Fprintf(os.Stderr, "error: decode: %v\n", err)
+				code = 1
 				break
 			}
 
@@ -121,6 +291,13 @@ func main() {
 			}
 		}
 
+		if classify && ok {
+			if solution, _, err = solution.Classify(); err != nil {
+				ok = false
+				fmt.Fprintf(os.Stderr, "error: classify: %v: %v\n", err, solution)
+			}
+		}
+
 		if normalize {
 			solution = solution.Normalize()
 		}
@@ -137,10 +314,74 @@ func main() {
 			}
 		}
 
-		if encode {
+		if stats {
+			tally.add(solution)
+		} else if diagnose {
+			if d, err := solution.Diagnose(); err != nil {
+				fmt.Fprintf(os.Stderr, "error: diagnose: %v: %v\n", err, solution)
+			} else {
+				encoder.Encode(d)
+			}
+		} else if outcomes {
+			if err := outcomeTables.write(solution); err != nil {
+				fmt.Fprintf(os.Stderr, "error: outcomes: %v: %v\n", err, solution)
+			}
+		} else if markdown || latex {
+			var table string
+			if markdown {
+				table, err = solution.Markdown()
+			} else {
+				table, err = solution.LaTeX()
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: table: %v: %v\n", err, solution)
+			} else {
+				fmt.Fprintf(os.Stdout, "%s\n", table)
+			}
+		} else if svg {
+			if doc, err := solution.SVG(); err != nil {
+				fmt.Fprintf(os.Stderr, "error: svg: %v: %v\n", err, solution)
+			} else {
+				fmt.Fprintf(os.Stdout, "%s", doc)
+			}
+		} else if dot {
+			var graph string
+			if incidence {
+				graph, err = solution.IncidenceDot()
+			} else {
+				graph, err = solution.Dot()
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: dot: %v: %v\n", err, solution)
+			} else {
+				fmt.Fprintf(os.Stdout, "%s", graph)
+			}
+		} else if repair {
+			if r, err := solution.Repair(repairs, depth); err != nil {
+				fmt.Fprintf(os.Stderr, "error: repair: %v: %v\n", err, solution)
+			} else if len(r) == 0 {
+				fmt.Fprintf(os.Stderr, "error: repair: no repair within %d edits: %v\n", depth, solution)
+			} else {
+				for _, e := range r {
+					encoder.Encode(e)
+				}
+			}
+		} else if tree {
+			if ok {
+				if t, err := solution.DecisionTree(); err != nil {
+					fmt.Fprintf(os.Stderr, "error: tree: %v: %v\n", err, solution)
+				} else {
+					encoder.Encode(t)
+				}
+			}
+		} else if encode {
 			if ok {
 				if n, err := solution.N(); err != nil {
 					fmt.Fprintf(os.Stderr, "error: N: %v: %v\n", err, solution)
+				} else if binary {
+					if err := numberWriter.Write(n); err != nil {
+						fmt.Fprintf(os.Stderr, "error: N: %v: %v\n", err, solution)
+					}
 				} else {
 					encoder.Encode(&n)
 				}
@@ -154,4 +395,40 @@ func main() {
 			}
 		}
 	}
+
+	if stats {
+		if err := tally.write(os.Stdout, statsFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "error: stats: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if code != 0 {
+		numberWriter.Flush()
+		os.Exit(code)
+	}
+}
+
+// Test each decision tree read from the decoder against all possibilities and
+// report the failures. Answers the exit code.
+func testTrees(decoder *json.Decoder) int {
+	code := 0
+	for {
+		t := &lib.DecisionTree{}
+		if err := decoder.Decode(t); err == io.EOF {
+			break
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "error: test-tree: %v\n", err)
+			return 1
+		}
+		if errors := lib.TestAllN(t.CoinCount(), t.Depth(), t.Decide); len(errors) > 0 {
+			for _, e := range errors {
+				fmt.Fprintf(os.Stderr, "%v", e)
+			}
+			code = 1
+		} else {
+			fmt.Fprintf(os.Stdout, "ok\n")
+		}
+	}
+	return code
 }
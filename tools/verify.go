@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jonseymour/12coins/lib"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// A counterexample records a solution number that failed verification.
+type counterexample struct {
+	N     uint   `json:"N"`
+	Error string `json:"error"`
+}
+
+// A checkpoint records the progress of a verification run. Every number
+// in [From, Next) has been verified.
+type checkpoint struct {
+	From            uint             `json:"from"`
+	Next            uint             `json:"next"`
+	To              uint             `json:"to"`
+	Counterexamples []counterexample `json:"counterexamples"`
+}
+
+// The result of verifying a chunk of numbers.
+type chunkResult struct {
+	start           uint
+	counterexamples []counterexample
+}
+
+// Load a checkpoint from the specified file, if it exists.
+func loadCheckpoint(file string) (*checkpoint, error) {
+	if file == "" {
+		return nil, nil
+	}
+	buf, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	cp := &checkpoint{}
+	if err := json.Unmarshal(buf, cp); err != nil {
+		return nil, fmt.Errorf("checkpoint: %s: %v", file, err)
+	}
+	return cp, nil
+}
+
+// Atomically replace the checkpoint file.
+func saveCheckpoint(file string, cp *checkpoint) error {
+	if file == "" {
+		return nil
+	}
+	buf, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// Verify a solution number. A panic is reported as the failure of that number so
+// that it does not end the run.
+func verifyN(n uint) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("panic: %v", e)
+		}
+	}()
+	return lib.VerifyN(n)
+}
+
+// Verify every solution number in [from, to) with a pool of workers, each of which
+// verifies one chunk of numbers at a time. Counterexamples are written to stdout
+// as they become part of the verified prefix. If a checkpoint file is specified,
+// the run resumes from it and it is rewritten at each interval and on interrupt.
+// If ranged is true, from and to were given explicitly and must agree with the range
+// of the checkpoint.
+//
+// Answers the exit code.
+func verify(from, to uint, ranged bool, workers int, chunk uint, file string, interval time.Duration) int {
+	if workers < 1 {
+		fmt.Fprintf(os.Stderr, "error: workers must be at least 1: %d\n", workers)
+		return 1
+	}
+	if chunk < 1 {
+		fmt.Fprintf(os.Stderr, "error: chunk must be at least 1: %d\n", chunk)
+		return 1
+	}
+	cp, err := loadCheckpoint(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	if cp == nil {
+		cp = &checkpoint{
+			From:            from,
+			Next:            from,
+			To:              to,
+			Counterexamples: []counterexample{},
+		}
+	} else if ranged && (from != cp.From || to != cp.To) {
+		fmt.Fprintf(os.Stderr, "error: the range [%d, %d) conflicts with the range [%d, %d) of the checkpoint: %s\n", from, to, cp.From, cp.To, file)
+		return 1
+	} else {
+		fmt.Fprintf(os.Stderr, "resuming: from: %d, next: %d, to: %d\n", cp.From, cp.Next, cp.To)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	starts := make(chan uint)
+	results := make(chan chunkResult)
+	stop := make(chan struct{})
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + chunk
+				if end > cp.To {
+					end = cp.To
+				}
+				result := chunkResult{
+					start:           start,
+					counterexamples: []counterexample{},
+				}
+				for n := start; n < end; n++ {
+					if err := verifyN(n); err != nil {
+						result.counterexamples = append(result.counterexamples, counterexample{
+							N:     n,
+							Error: err.Error(),
+						})
+					}
+				}
+				results <- result
+			}
+		}()
+	}
+
+	go func() {
+		defer close(starts)
+		for start := cp.Next; start < cp.To; start += chunk {
+			select {
+			case starts <- start:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	pending := make(map[uint][]counterexample)
+	began := time.Now()
+	initial := cp.Next
+	stopped := false
+
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				if err := saveCheckpoint(file, cp); err != nil {
+					fmt.Fprintf(os.Stderr, "error: checkpoint: %v\n", err)
+					return 1
+				}
+				if stopped {
+					fmt.Fprintf(os.Stderr, "interrupted: next: %d\n", cp.Next)
+					return 1
+				}
+				fmt.Fprintf(os.Stderr, "verified: [%d, %d): %d counterexamples\n", cp.From, cp.To, len(cp.Counterexamples))
+				if len(cp.Counterexamples) > 0 {
+					return 1
+				}
+				return 0
+			}
+			pending[result.start] = result.counterexamples
+			for {
+				ce, ok := pending[cp.Next]
+				if !ok {
+					break
+				}
+				delete(pending, cp.Next)
+				for _, e := range ce {
+					encoder.Encode(&e)
+				}
+				cp.Counterexamples = append(cp.Counterexamples, ce...)
+				cp.Next += chunk
+				if cp.Next > cp.To {
+					cp.Next = cp.To
+				}
+			}
+		case <-ticker.C:
+			if err := saveCheckpoint(file, cp); err != nil {
+				fmt.Fprintf(os.Stderr, "error: checkpoint: %v\n", err)
+			}
+			elapsed := time.Since(began)
+			rate := float64(cp.Next-initial) / elapsed.Seconds()
+			fmt.Fprintf(os.Stderr, "progress: next: %d, %.0f/s, %d counterexamples\n", cp.Next, rate, len(cp.Counterexamples))
+		case <-interrupts:
+			if !stopped {
+				stopped = true
+				close(stop)
+			}
+		}
+	}
+}